@@ -5,7 +5,11 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"treco/conf"
+	"treco/migration"
+	"treco/report"
 	"treco/server"
 	"treco/storage"
 
@@ -30,24 +34,32 @@ func newCollectCommand() *cobra.Command {
 				_ = (*handler).Close()
 			}()
 
-			//DB setup
-			err = (*handler).Setup(server.DBEntities...)
+			//DB setup, schema is locked only when migrations are pending
+			err = migration.Up((*handler).GetDB())
 			exitOnError(err)
 
 			//validate flags
 			err = validateFlags(cfg)
 			exitOnError(err)
 
-			//check for report file
-			reportFile, err := os.OpenFile(cfg.ReportFile, os.O_RDONLY, 0600)
+			//check for report files
+			paths, err := reportFilePaths(cfg.ReportFile)
+			exitOnError(err)
+
+			reports, files, err := openFiles(paths)
+			defer closeFiles(files)
+			exitOnError(err)
+
+			//check for coverage files, coverage is computed from them when set
+			coveragePaths, err := reportFilePaths(cfg.CoverageFile)
+			exitOnError(err)
+
+			coverageReports, coverageFiles, err := openFiles(coveragePaths)
+			defer closeFiles(coverageFiles)
 			exitOnError(err)
-			defer func() {
-				_ = reportFile.Close()
-			}()
 
-			// Process file
-			var rf io.Reader = reportFile
-			err = server.Process(cfg, rf)
+			// Process files
+			err = server.Process(cfg, reports, coverageReports...)
 			exitOnError(err)
 
 			log.Println("results uploaded successfully")
@@ -59,11 +71,12 @@ func newCollectCommand() *cobra.Command {
 	flags.StringVarP(&cfg.Build, "build", "b", os.Getenv(server.BuildID), "CI Build name or number to uniquely identify the Build")
 	flags.StringVarP(&cfg.Environment, "environment", "e", os.Getenv(server.Environment), "Environment on which the Build is executed")
 	flags.StringVarP(&cfg.Jira, "jira", "j", os.Getenv(server.Jira), "Jira project name")
-	flags.StringVarP(&cfg.ReportFile, "report", "r", os.Getenv(server.ReportFile), "input file containing test reports")
-	flags.StringVarP(&cfg.ReportFormat, "format", "f", os.Getenv(server.ReportFormat), "report of report file")
+	flags.StringVarP(&cfg.ReportFile, "report", "r", os.Getenv(server.ReportFile), "input file containing test reports, multiple files or glob patterns can be comma separated")
+	flags.StringVarP(&cfg.ReportFormat, "format", "f", os.Getenv(server.ReportFormat), fmt.Sprintf("format of report file, one of %v. Detected from the report when not set", strings.Join(report.Formats(), ", ")))
 	flags.StringVarP(&cfg.Service, "service", "s", os.Getenv(server.Service), "Service name")
-	flags.StringVarP(&cfg.TestType, "type", "t", os.Getenv(server.TestType), "type of tests executed. 'unit', 'contract', 'integration' or 'e2e")
-	flags.StringVarP(&cfg.Coverage, "coverage", "c", os.Getenv(server.Coverage), "statement level code coverage")
+	flags.StringVarP(&cfg.TestType, "type", "t", os.Getenv(server.TestType), "type of tests executed. 'unit', 'contract', 'integration', 'e2e' or 'performance'")
+	flags.StringVarP(&cfg.Coverage, "coverage", "c", os.Getenv(server.Coverage), "statement level code coverage, not needed when coverage file is set")
+	flags.StringVar(&cfg.CoverageFile, "coverage-file", os.Getenv(server.CoverageFile), "cobertura, jacoco, lcov or go coverprofile file to compute coverage from, multiple files or glob patterns can be comma separated")
 
 	return collectCmd
 }
@@ -72,20 +85,70 @@ var (
 	errMissingArguments = fmt.Errorf("\nmissing arguments, please run `treco --help` for more info\n"+
 		"\nyou can also supply arguments via following ENVIRONMENT variables\n"+
 		"%v ", server.RequiredParams)
+
+	errNoReportFiles = "no report files found at %v"
 )
 
 // validate flags sent to collect command
 func validateFlags(cfg conf.Config) error {
 	//check for empty flags
 	log.Println("validating parameters")
-	if cfg.ReportFile == "" || cfg.ReportFormat == "" || cfg.Service == "" || cfg.TestType == "" || cfg.Build == "" ||
-		cfg.Jira == "" || cfg.Environment == "" || cfg.Coverage == "" {
+	if cfg.ReportFile == "" || cfg.Service == "" || cfg.TestType == "" || cfg.Build == "" ||
+		cfg.Jira == "" || cfg.Environment == "" || (cfg.Coverage == "" && cfg.CoverageFile == "") {
 		return errMissingArguments
 	}
 
 	return server.ValidateParams(cfg.TestType, cfg.ReportFormat, cfg.Coverage)
 }
 
+// reportFilePaths expands comma separated file paths and glob patterns
+func reportFilePaths(reportFiles string) ([]string, error) {
+	paths := make([]string, 0)
+	for _, pattern := range strings.Split(reportFiles, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf(errNoReportFiles, pattern)
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	return paths, nil
+}
+
+// openFiles opens the files for reading, compressed files are detected from their contents when parsed, whatever
+// their extension. Files are closed by the caller
+func openFiles(paths []string) ([]io.Reader, []*os.File, error) {
+	readers := make([]io.Reader, 0, len(paths))
+	files := make([]*os.File, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.OpenFile(path, os.O_RDONLY, 0600)
+		if err != nil {
+			return nil, files, err
+		}
+
+		files = append(files, f)
+		readers = append(readers, f)
+	}
+
+	return readers, files, nil
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
+
 // exits ith fatal error
 func exitOnError(e error) {
 	if e != nil {
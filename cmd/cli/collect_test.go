@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"treco/conf"
@@ -27,6 +30,11 @@ func TestValidateFlagsWithMissingFlags(t *testing.T) {
 		field := configValue.Field(fieldIdx)
 		fieldName := configType.Field(fieldIdx).Name
 
+		//Report format and coverage file are optional
+		if fieldName == "ReportFormat" || fieldName == "CoverageFile" {
+			continue
+		}
+
 		currentFieldValue := field.String()
 		field.SetString("") //Set each field to empty
 
@@ -44,3 +52,39 @@ func TestValidateFlagsWithValidFlags(t *testing.T) {
 	err := validateFlags(testConfig)
 	require.NoError(t, err)
 }
+
+func TestValidateFlagsWithoutReportFormat(t *testing.T) {
+	cfg := testConfig
+	cfg.ReportFormat = ""
+
+	err := validateFlags(cfg)
+	require.NoError(t, err)
+}
+
+func TestValidateFlagsWithCoverageFile(t *testing.T) {
+	cfg := testConfig
+	cfg.Coverage = ""
+	cfg.CoverageFile = "coverage.out"
+
+	err := validateFlags(cfg)
+	require.NoError(t, err)
+}
+
+func TestReportFilePaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"TEST-a.xml", "TEST-b.xml", "summary.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte{}, 0600))
+	}
+
+	paths, err := reportFilePaths(filepath.Join(dir, "*.xml") + ", " + filepath.Join(dir, "summary.txt"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "TEST-a.xml"),
+		filepath.Join(dir, "TEST-b.xml"),
+		filepath.Join(dir, "summary.txt"),
+	}, paths)
+
+	missing := filepath.Join(dir, "missing.xml")
+	_, err = reportFilePaths(missing)
+	require.Equal(t, fmt.Errorf(errNoReportFiles, missing), err)
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"treco/conf"
+	"treco/migration"
+	"treco/storage"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// newMigrateCommand
+func newMigrateCommand() *cobra.Command {
+	var cfgFile string
+	var steps int
+	var force bool
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Applies, reverts or lists versioned migrations of the DB schema",
+	}
+
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Applies pending migrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			withDB(cfgFile, func(db *gorm.DB) error {
+				return migration.Up(db)
+			})
+
+			log.Println("schema is up to date")
+		},
+	}
+
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Reverts the latest applied migrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			withDB(cfgFile, func(db *gorm.DB) error {
+				return migration.Down(db, steps, force)
+			})
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Lists migrations and whether they are applied",
+		Run: func(cmd *cobra.Command, args []string) {
+			withDB(cfgFile, func(db *gorm.DB) error {
+				states, err := migration.Status(db)
+				if err != nil {
+					return err
+				}
+
+				return printMigrationStatus(os.Stdout, states)
+			})
+		},
+	}
+
+	migrateCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file")
+	downCmd.Flags().IntVarP(&steps, "steps", "n", 1, "number of migrations to revert")
+	downCmd.Flags().BoolVar(&force, "force", false, "revert migrations which drop stored data, such as the initial schema")
+
+	migrateCmd.AddCommand(upCmd, downCmd, statusCmd)
+	return migrateCmd
+}
+
+// withDB connects to storage and runs fn with the DB, exits on error
+func withDB(cfgFile string, fn func(db *gorm.DB) error) {
+	if cfgFile != "" {
+		exitOnError(conf.LoadEnvFromFile(cfgFile))
+	}
+
+	err := storage.New()
+	exitOnError(err)
+
+	handler := storage.Handler()
+	defer func() {
+		_ = (*handler).Close()
+	}()
+
+	exitOnError(fn((*handler).GetDB()))
+}
+
+// printMigrationStatus writes a line for each migration with the time it was applied at
+func printMigrationStatus(w io.Writer, states []migration.State) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+	for _, state := range states {
+		appliedAt := "pending"
+		if state.Applied {
+			appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		_, _ = fmt.Fprintf(tw, "%v\t%v\t%v\n", state.Version, state.Name, appliedAt)
+	}
+
+	return tw.Flush()
+}
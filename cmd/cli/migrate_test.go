@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"treco/migration"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintMigrationStatus(t *testing.T) {
+	states := []migration.State{
+		{
+			Migration: migration.Migration{Version: "0001", Name: "initial schema"},
+			Applied:   true,
+			AppliedAt: time.Date(2023, 8, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			Migration: migration.Migration{Version: "0002", Name: "scenario owners"},
+		},
+	}
+
+	var b bytes.Buffer
+	err := printMigrationStatus(&b, states)
+	require.NoError(t, err)
+	require.Equal(t, "VERSION  NAME             APPLIED AT\n"+
+		"0001     initial schema   2023-08-01 10:30:00\n"+
+		"0002     scenario owners  pending\n", b.String())
+}
@@ -10,18 +10,20 @@ import (
 func newServeCommand() *cobra.Command {
 	var port int
 	var cfgFile string
+	var skipMigrations bool
 
 	serveCmd := cobra.Command{
 		Use:   "serve",
 		Short: "Runs as a web server",
 		Run: func(cmd *cobra.Command, args []string) {
-			server.Start(cfgFile, port)
+			server.Start(cfgFile, port, skipMigrations)
 		},
 	}
 
 	flags := serveCmd.Flags()
 	flags.IntVarP(&port, "port", "p", 8080, "port for server to run")
 	flags.StringVarP(&cfgFile, "config", "c", "", "config file")
+	flags.BoolVar(&skipMigrations, "skip-migrations", false, "do not apply pending migrations on start, and refuse to start when the schema is behind. Migrations are then applied with `treco migrate up`")
 
 	return &serveCmd
 }
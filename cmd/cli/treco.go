@@ -13,6 +13,7 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(newCollectCommand())
 	rootCmd.AddCommand(newServeCommand())
+	rootCmd.AddCommand(newMigrateCommand())
 }
 
 // Execute ...
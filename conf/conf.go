@@ -20,6 +20,7 @@ type Config struct {
 	Service      string
 	TestType     string
 	Coverage     string
+	CoverageFile string
 }
 
 // LoadEnvFromFile ...
@@ -0,0 +1,162 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{
+		Version:   "0001",
+		Name:      "initial schema",
+		Up:        initialSchemaUp,
+		Down:      initialSchemaDown,
+		DropsData: true,
+	})
+}
+
+// initialSchemaUp creates the schema as of this migration, models are copied here so that later changes of the model
+// are made by later migrations. Schemas created by AutoMigrate before migrations were versioned are brought up to date
+func initialSchemaUp(tx *gorm.DB) error {
+	type FileCoverage struct {
+		ID                uint    `gorm:"primarykey"`
+		PackageCoverageID uint    `gorm:",not null"`
+		Name              string  `gorm:",not null"`
+		LinesCovered      uint    `gorm:"default:0"`
+		LinesValid        uint    `gorm:"default:0"`
+		BranchesCovered   uint    `gorm:"default:0"`
+		BranchesValid     uint    `gorm:"default:0"`
+		LineCoverage      float64 `gorm:"default:0"`
+		BranchCoverage    float64 `gorm:"default:0"`
+		CreatedAt         time.Time
+		UpdatedAt         time.Time
+	}
+
+	type PackageCoverage struct {
+		ID              uint    `gorm:"primarykey"`
+		SuiteResultID   uint    `gorm:",not null"`
+		Name            string  `gorm:",not null"`
+		LinesCovered    uint    `gorm:"default:0"`
+		LinesValid      uint    `gorm:"default:0"`
+		BranchesCovered uint    `gorm:"default:0"`
+		BranchesValid   uint    `gorm:"default:0"`
+		LineCoverage    float64 `gorm:"default:0"`
+		BranchCoverage  float64 `gorm:"default:0"`
+		Files           []FileCoverage
+		CreatedAt       time.Time
+		UpdatedAt       time.Time
+	}
+
+	type SuiteResultProperty struct {
+		ID            uint   `gorm:"primarykey"`
+		SuiteResultID uint   `gorm:",not null"`
+		Name          string `gorm:",not null"`
+		Value         string
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+	}
+
+	type ScenarioResultProperty struct {
+		ID               uint   `gorm:"primarykey"`
+		ScenarioResultID uint   `gorm:",not null"`
+		Name             string `gorm:",not null"`
+		Value            string
+		CreatedAt        time.Time
+		UpdatedAt        time.Time
+	}
+
+	type BenchmarkMetric struct {
+		ID               uint    `gorm:"primarykey"`
+		ScenarioResultID uint    `gorm:",not null"`
+		ScenarioID       uint    `gorm:",not null"`
+		Name             string  `gorm:",not null"`
+		Value            float64 `gorm:"default:0"`
+		Error            float64 `gorm:"default:0"`
+		Unit             string
+		CreatedAt        time.Time
+		UpdatedAt        time.Time
+	}
+
+	type ScenarioResult struct {
+		ID            uint    `gorm:"primarykey"`
+		ScenarioID    uint    `gorm:",not null"`
+		SuiteResultID uint    `gorm:",not null"`
+		Status        string  `gorm:",not null"`
+		TimeTaken     float64 `gorm:"default:0"`
+		Attempts      uint    `gorm:"default:1"`
+		Parameters    string
+		Project       string
+		Message       string
+		FailureType   string
+		StackTrace    string
+		SystemOut     string
+		SystemErr     string
+		SuiteName     string
+		SuitePath     string
+		Hostname      string
+		Timestamp     *time.Time
+		Properties    []ScenarioResultProperty
+		Metrics       []BenchmarkMetric
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+	}
+
+	type SuiteResult struct {
+		ID              uint    `gorm:"primarykey"`
+		Build           string  `gorm:"uniqueIndex:ui_suite_result"`
+		TestType        string  `gorm:"uniqueIndex:ui_suite_result"`
+		Service         string  `gorm:"not null"`
+		Environment     string  `gorm:"not null"`
+		TimeTaken       float64 `gorm:"not null"`
+		TotalExecuted   uint    `gorm:"default:0"`
+		TotalPassed     uint    `gorm:"default:0"`
+		TotalFailed     uint    `gorm:"default:0"`
+		TotalSkipped    uint    `gorm:"default:0"`
+		TotalFlaky      uint    `gorm:"default:0"`
+		TotalsMismatch  bool    `gorm:"default:false"`
+		Coverage        float64 `gorm:"default:0"`
+		BranchCoverage  float64 `gorm:"default:0"`
+		ReportFormat    string
+		Properties      []SuiteResultProperty
+		Packages        []PackageCoverage
+		ScenarioResults []ScenarioResult
+		CreatedAt       time.Time
+		UpdatedAt       time.Time
+	}
+
+	type Tag struct {
+		ID        string `gorm:"primaryKey"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	type Feature struct {
+		ID        string `gorm:"primaryKey"`
+		Title     string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	type Scenario struct {
+		ID        uint      `gorm:"primarykey"`
+		Name      string    `gorm:"uniqueIndex:ui_scenario"`
+		Class     string    `gorm:"uniqueIndex:ui_scenario"`
+		TestType  string    `gorm:"uniqueIndex:ui_scenario"`
+		Service   string    `gorm:"uniqueIndex:ui_scenario"`
+		Features  []Feature `gorm:"many2many:feature_scenarios"`
+		Tags      []Tag     `gorm:"many2many:scenario_tags"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	return tx.AutoMigrate(&SuiteResult{}, &ScenarioResult{}, &Scenario{}, &Feature{}, &Tag{}, &SuiteResultProperty{},
+		&ScenarioResultProperty{}, &PackageCoverage{}, &FileCoverage{}, &BenchmarkMetric{})
+}
+
+// initialSchemaDown drops every table, tables referencing others are dropped first
+func initialSchemaDown(tx *gorm.DB) error {
+	return tx.Migrator().DropTable("feature_scenarios", "scenario_tags", "benchmark_metrics",
+		"scenario_result_properties", "scenario_results", "file_coverages", "package_coverages",
+		"suite_result_properties", "suite_results", "scenarios", "features", "tags")
+}
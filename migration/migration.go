@@ -0,0 +1,276 @@
+/*
+Package migration handles versioned and reversible changes of the DB schema
+*/
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Key of the lock held while migrating, so that only one instance changes the schema at a time
+const lockKey = 7305621
+
+var (
+	errSchemaBehind       = "schema is behind, %v migrations pending, please run `treco migrate up`"
+	errUnknownMigration   = "migration %v is applied but not known to this version of treco"
+	errUnableToLockSchema = "unable to lock schema for migration: %w"
+	errLockNotGranted     = "lock not granted, GET_LOCK returned %v"
+	errMigrationFailed    = "migration %v %v failed: %w"
+	errRevertFailed       = "reverting migration %v %v failed: %w"
+	errRevertDropsData    = "reverting migration %v %v drops the data stored in its tables, pass --force to revert it"
+
+	migrations = make([]Migration, 0)
+)
+
+// Migration is a versioned change of the schema, which can be reverted with Down. Up and Down run in a transaction,
+// but MySQL and MariaDB commit each DDL statement on its own, hence a migration failing there is not rolled back.
+// DDL must be idempotent, e.g. CREATE TABLE IF NOT EXISTS or guarded by Migrator().HasColumn, so that a failed
+// migration can be applied again. Migrations whose Down drops stored data, e.g. the tables created by Up, set DropsData
+// and are reverted only when forced
+type Migration struct {
+	Version   string
+	Name      string
+	Up        func(tx *gorm.DB) error
+	Down      func(tx *gorm.DB) error
+	DropsData bool
+}
+
+// SchemaMigration struct, a migration applied to the DB
+type SchemaMigration struct {
+	Version   string `gorm:"primaryKey;size:32"`
+	Name      string `gorm:"not null"`
+	AppliedAt time.Time
+}
+
+// State of a migration
+type State struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// register adds a migration, migrations are kept sorted by version. Duplicate versions are a programming error
+func register(m Migration) {
+	for _, existing := range migrations {
+		if existing.Version == m.Version {
+			panic("migration: version registered twice " + m.Version)
+		}
+	}
+
+	migrations = append(migrations, m)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+}
+
+// Up applies pending migrations in order of their version, the schema is locked only when migrations are pending
+func Up(db *gorm.DB) error {
+	pending, err := Pending(db)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	return withLock(db, func(conn *gorm.DB) error {
+		// Another instance may have migrated while waiting for the lock
+		pending, err := Pending(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range pending {
+			log.Printf("applying migration %v %v\n", m.Version, m.Name)
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+
+				return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			}); err != nil {
+				return fmt.Errorf(errMigrationFailed, m.Version, m.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Down reverts the given number of latest applied migrations
+func Down(db *gorm.DB, steps int, force bool) error {
+	return withLock(db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		// Every migration is checked before reverting any, so that the schema is not left half way
+		revert := make([]Migration, 0, steps)
+		for i := len(applied) - 1; i >= 0 && len(revert) < steps; i-- {
+			m, ok := find(applied[i].Version)
+			if !ok {
+				return fmt.Errorf(errUnknownMigration, applied[i].Version)
+			}
+
+			if m.DropsData && !force {
+				return fmt.Errorf(errRevertDropsData, m.Version, m.Name)
+			}
+
+			revert = append(revert, m)
+		}
+
+		for _, m := range revert {
+			log.Printf("reverting migration %v %v\n", m.Version, m.Name)
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+
+				return tx.Delete(&SchemaMigration{Version: m.Version}).Error
+			}); err != nil {
+				return fmt.Errorf(errRevertFailed, m.Version, m.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status returns every known migration and whether it is applied
+func Status(db *gorm.DB) ([]State, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := make(map[string]time.Time, len(applied))
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+
+	states := make([]State, 0, len(migrations))
+	for _, m := range migrations {
+		at, ok := appliedAt[m.Version]
+		states = append(states, State{Migration: m, Applied: ok, AppliedAt: at})
+	}
+
+	return states, nil
+}
+
+// Pending returns migrations which are not applied yet
+func Pending(db *gorm.DB) ([]Migration, error) {
+	states, err := Status(db)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]Migration, 0)
+	for _, state := range states {
+		if !state.Applied {
+			pending = append(pending, state.Migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Check returns an error when migrations are pending, without applying them
+func Check(db *gorm.DB) error {
+	pending, err := Pending(db)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf(errSchemaBehind, len(pending))
+	}
+
+	return nil
+}
+
+// appliedMigrations returns applied migrations sorted by version, none are applied when the table does not exist yet
+func appliedMigrations(db *gorm.DB) ([]SchemaMigration, error) {
+	applied := make([]SchemaMigration, 0)
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return applied, nil
+	}
+
+	err := db.Order("version").Find(&applied).Error
+	return applied, err
+}
+
+// find returns the known migration of the version
+func find(version string) (Migration, bool) {
+	for _, m := range migrations {
+		if m.Version == version {
+			return m, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// withLock runs fn on a single connection holding a lock on the schema, and creates the table of applied migrations
+func withLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		// New session, so that statements run on the connection do not share their conditions
+		conn = conn.Session(&gorm.Session{})
+
+		unlock, err := lockSchema(conn)
+		if err != nil {
+			return fmt.Errorf(errUnableToLockSchema, err)
+		}
+
+		defer unlock()
+
+		if err := conn.AutoMigrate(&SchemaMigration{}); err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
+
+// lockSchema locks the schema until the returned func is called. Postgres and MySQL locks are held by the connection,
+// SQLite locks the DB file on each write instead
+func lockSchema(conn *gorm.DB) (func(), error) {
+	switch conn.Dialector.Name() {
+	case "postgres":
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			return nil, err
+		}
+
+		return func() {
+			_ = conn.Exec("SELECT pg_advisory_unlock(?)", lockKey).Error
+		}, nil
+	case "mysql":
+		// GET_LOCK does not fail when the lock is not granted, it returns 0 on timeout and NULL e.g. when interrupted
+		var locked sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(CONCAT('treco_', ?), -1)", lockKey).Row().Scan(&locked); err != nil {
+			return nil, err
+		}
+
+		if !locked.Valid || locked.Int64 != 1 {
+			return nil, fmt.Errorf(errLockNotGranted, lockResult(locked))
+		}
+
+		return func() {
+			_ = conn.Exec("SELECT RELEASE_LOCK(CONCAT('treco_', ?))", lockKey).Error
+		}, nil
+	}
+
+	return func() {}, nil
+}
+
+// lockResult formats the result of GET_LOCK
+func lockResult(locked sql.NullInt64) string {
+	if !locked.Valid {
+		return "NULL"
+	}
+
+	return fmt.Sprint(locked.Int64)
+}
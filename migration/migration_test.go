@@ -0,0 +1,215 @@
+//go:build cgo
+
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var modelEntities = []interface{}{&model.SuiteResult{}, &model.ScenarioResult{}, &model.Scenario{}, &model.Feature{},
+	&model.Tag{}, &model.SuiteResultProperty{}, &model.ScenarioResultProperty{}, &model.PackageCoverage{},
+	&model.FileCoverage{}, &model.BenchmarkMetric{}}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "treco.db")), &gorm.Config{})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		_ = sqlDB.Close()
+	})
+
+	return db
+}
+
+// withMigrations replaces known migrations for the duration of the test
+func withMigrations(t *testing.T, ms ...Migration) {
+	known := migrations
+	t.Cleanup(func() {
+		migrations = known
+	})
+
+	migrations = make([]Migration, 0)
+	for _, m := range append(append([]Migration{}, known...), ms...) {
+		register(m)
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	db := openTestDB(t)
+
+	states, err := Status(db)
+	require.NoError(t, err)
+	require.Len(t, states, len(migrations))
+	require.False(t, states[0].Applied)
+	require.Equal(t, fmt.Errorf(errSchemaBehind, len(migrations)), Check(db))
+
+	require.NoError(t, Up(db))
+	require.NoError(t, Check(db))
+	require.True(t, db.Migrator().HasTable(&model.Scenario{}))
+
+	states, err = Status(db)
+	require.NoError(t, err)
+	require.True(t, states[0].Applied)
+	require.False(t, states[0].AppliedAt.IsZero())
+
+	// Nothing to apply
+	require.NoError(t, Up(db))
+
+	require.NoError(t, Down(db, len(migrations), true))
+	require.False(t, db.Migrator().HasTable(&model.Scenario{}))
+	require.False(t, db.Migrator().HasTable("scenario_tags"))
+
+	pending, err := Pending(db)
+	require.NoError(t, err)
+	require.Len(t, pending, len(migrations))
+}
+
+func TestMigrationsMatchModel(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, Up(db))
+
+	// A model change without a migration is caught here
+	for _, entity := range modelEntities {
+		s, err := schema.Parse(entity, &sync.Map{}, db.NamingStrategy)
+		require.NoError(t, err)
+
+		for _, field := range s.Fields {
+			if field.DBName == "" {
+				continue
+			}
+
+			require.True(t, db.Migrator().HasColumn(entity, field.DBName), "%v.%v has no migration", s.Table, field.DBName)
+		}
+
+		for _, rel := range s.Relationships.Many2Many {
+			require.True(t, db.Migrator().HasTable(rel.JoinTable.Table), "%v has no migration", rel.JoinTable.Table)
+		}
+	}
+}
+
+func TestUpOnAutoMigratedSchema(t *testing.T) {
+	db := openTestDB(t)
+
+	// Schema created before migrations were versioned
+	require.NoError(t, db.AutoMigrate(modelEntities...))
+	require.NoError(t, db.Create(&model.Scenario{Name: "test_login", Class: "LoginTest", TestType: "e2e", Service: "abc"}).Error)
+
+	require.NoError(t, Up(db))
+	require.NoError(t, Check(db))
+
+	var count int64
+	require.NoError(t, db.Model(&model.Scenario{}).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+}
+
+func TestFailedMigrationIsNotApplied(t *testing.T) {
+	withMigrations(t, Migration{
+		Version: "9999",
+		Name:    "failing",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("CREATE TABLE partial (id integer)").Error; err != nil {
+				return err
+			}
+
+			return errors.New("some error")
+		},
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+
+	db := openTestDB(t)
+	err := Up(db)
+	require.Error(t, err)
+	require.Equal(t, "migration 9999 failing failed: some error", err.Error())
+
+	// Earlier migrations stay applied, failed one is rolled back
+	pending, err := Pending(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "9999", pending[0].Version)
+	require.False(t, db.Migrator().HasTable("partial"))
+}
+
+func TestReversibleMigration(t *testing.T) {
+	withMigrations(t, Migration{
+		Version: "9999",
+		Name:    "rename scenario class",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE scenarios RENAME COLUMN class TO class_name").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE scenarios RENAME COLUMN class_name TO class").Error
+		},
+	})
+
+	db := openTestDB(t)
+	require.NoError(t, Up(db))
+	require.True(t, db.Migrator().HasColumn("scenarios", "class_name"))
+
+	require.NoError(t, Down(db, 1, false))
+	require.True(t, db.Migrator().HasColumn("scenarios", "class"))
+	require.False(t, db.Migrator().HasColumn("scenarios", "class_name"))
+
+	pending, err := Pending(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+}
+
+func TestDownInitialSchemaIsForced(t *testing.T) {
+	withMigrations(t, Migration{
+		Version: "9999",
+		Name:    "scenario owner",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE scenarios ADD COLUMN owner varchar(191)").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE scenarios DROP COLUMN owner").Error
+		},
+	})
+
+	db := openTestDB(t)
+	require.NoError(t, Up(db))
+
+	// Nothing is reverted when one of the migrations drops data
+	err := Down(db, 2, false)
+	require.Equal(t, fmt.Errorf(errRevertDropsData, "0001", "initial schema"), err)
+	require.True(t, db.Migrator().HasColumn("scenarios", "owner"))
+
+	require.NoError(t, Down(db, 1, false))
+	require.False(t, db.Migrator().HasColumn("scenarios", "owner"))
+
+	err = Down(db, 1, false)
+	require.Equal(t, fmt.Errorf(errRevertDropsData, "0001", "initial schema"), err)
+	require.True(t, db.Migrator().HasTable(&model.Scenario{}))
+
+	require.NoError(t, Down(db, 1, true))
+	require.False(t, db.Migrator().HasTable(&model.Scenario{}))
+}
+
+func TestDownUnknownMigration(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, Up(db))
+	require.NoError(t, db.Create(&SchemaMigration{Version: "9999", Name: "newer treco"}).Error)
+
+	err := Down(db, 1, false)
+	require.Equal(t, fmt.Errorf(errUnknownMigration, "9999"), err)
+}
+
+func TestDuplicateVersionPanics(t *testing.T) {
+	withMigrations(t)
+	require.Panics(t, func() {
+		register(Migration{Version: "0001"})
+	})
+}
@@ -4,14 +4,22 @@ Package model defines DAO
 package model
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
 	"treco/storage"
+	"unicode/utf8"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+var (
+	errMissingScenarioID = "no scenario saved for %v of class %v"
+	errValueTooLong      = "%v %q is longer than %v characters, the most which can be stored in an index"
+)
+
 // Data from report
 type Data struct {
 	//DbHandler    *storage.DBHandler
@@ -32,12 +40,59 @@ type SuiteResult struct {
 	TotalPassed     uint    `gorm:"default:0"`
 	TotalFailed     uint    `gorm:"default:0"`
 	TotalSkipped    uint    `gorm:"default:0"`
+	TotalFlaky      uint    `gorm:"default:0"`
+	TotalsMismatch  bool    `gorm:"default:false"`
 	Coverage        float64 `gorm:"default:0"`
+	BranchCoverage  float64 `gorm:"default:0"`
+	ReportFormat    string
+	Properties      []SuiteResultProperty
+	Packages        []PackageCoverage
 	ScenarioResults []ScenarioResult
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
 
+// SuiteResultProperty struct with key value metadata of a suite result
+type SuiteResultProperty struct {
+	ID            uint   `gorm:"primarykey"`
+	SuiteResultID uint   `gorm:",not null"`
+	Name          string `gorm:",not null"`
+	Value         string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// PackageCoverage struct with line and branch coverage of a package or module, coverage values are percentages
+type PackageCoverage struct {
+	ID              uint    `gorm:"primarykey"`
+	SuiteResultID   uint    `gorm:",not null"`
+	Name            string  `gorm:",not null"`
+	LinesCovered    uint    `gorm:"default:0"`
+	LinesValid      uint    `gorm:"default:0"`
+	BranchesCovered uint    `gorm:"default:0"`
+	BranchesValid   uint    `gorm:"default:0"`
+	LineCoverage    float64 `gorm:"default:0"`
+	BranchCoverage  float64 `gorm:"default:0"`
+	Files           []FileCoverage
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// FileCoverage struct with line and branch coverage of a source file
+type FileCoverage struct {
+	ID                uint    `gorm:"primarykey"`
+	PackageCoverageID uint    `gorm:",not null"`
+	Name              string  `gorm:",not null"`
+	LinesCovered      uint    `gorm:"default:0"`
+	LinesValid        uint    `gorm:"default:0"`
+	BranchesCovered   uint    `gorm:"default:0"`
+	BranchesValid     uint    `gorm:"default:0"`
+	LineCoverage      float64 `gorm:"default:0"`
+	BranchCoverage    float64 `gorm:"default:0"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
 // ScenarioResult struct with execution details
 type ScenarioResult struct {
 	ID            uint     `gorm:"primarykey"`
@@ -47,11 +102,50 @@ type ScenarioResult struct {
 	Class         string   `gorm:"-"`
 	Status        string   `gorm:",not null"`
 	TimeTaken     float64  `gorm:"default:0"`
+	Attempts      uint     `gorm:"default:1"`
 	Features      []string `gorm:"-"`
+	Tags          []string `gorm:"-"`
+	Parameters    string
+	Project       string
+	Message       string
+	FailureType   string
+	StackTrace    string
+	SystemOut     string
+	SystemErr     string
+	SuiteName     string
+	SuitePath     string
+	Hostname      string
+	Timestamp     *time.Time
+	Properties    []ScenarioResultProperty
+	Metrics       []BenchmarkMetric
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
 
+// ScenarioResultProperty struct with key value metadata of a scenario result
+type ScenarioResultProperty struct {
+	ID               uint   `gorm:"primarykey"`
+	ScenarioResultID uint   `gorm:",not null"`
+	Name             string `gorm:",not null"`
+	Value            string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// BenchmarkMetric struct with a measurement of a benchmark, e.g. time, memory or allocations per operation, or
+// throughput. Scenario is set along with the scenario result so that a benchmark can be tracked across builds
+type BenchmarkMetric struct {
+	ID               uint    `gorm:"primarykey"`
+	ScenarioResultID uint    `gorm:",not null"`
+	ScenarioID       uint    `gorm:",not null"`
+	Name             string  `gorm:",not null"`
+	Value            float64 `gorm:"default:0"`
+	Error            float64 `gorm:"default:0"`
+	Unit             string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
 // Scenario struct with details of scenario
 type Scenario struct {
 	ID        uint      `gorm:"primarykey"`
@@ -60,6 +154,15 @@ type Scenario struct {
 	TestType  string    `gorm:"uniqueIndex:ui_scenario"`
 	Service   string    `gorm:"uniqueIndex:ui_scenario"`
 	Features  []Feature `gorm:"many2many:feature_scenarios"`
+	Tags      []Tag     `gorm:"many2many:scenario_tags"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Tag struct for scenario tags or groups
+type Tag struct {
+	ID        string     `gorm:"primaryKey"`
+	Scenarios []Scenario `gorm:"many2many:scenario_tags"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -76,51 +179,172 @@ type Feature struct {
 // Save data to DB
 func (d *Data) Save(dbh *storage.DBHandler) error {
 	suiteResult := &d.SuiteResult
-	scenarioResults := suiteResult.ScenarioResults
+	return saveToDB(dbh, suiteResult, d.scenarios())
+}
+
+// scenarioKey is the natural key of a scenario within a suite result, test type and service are the same for all of them
+type scenarioKey struct {
+	name  string
+	class string
+}
+
+// scenarios returns a scenario for each distinct name and class of scenario results. Same scenario can be reported
+// more than once, e.g. on retries or when a class is present in more than one module, features and tags of which are
+// merged
+func (d *Data) scenarios() []Scenario {
+	scenarioResults := d.SuiteResult.ScenarioResults
 
 	scenarios := make([]Scenario, 0, len(scenarioResults)) //scenarios
+	index := make(map[scenarioKey]int, len(scenarioResults))
 
 	// Loop through scenarios
 	for _, scenarioResult := range scenarioResults {
+		features := getFeaturesFromScenarioResult(d.Jira, scenarioResult)
+		tags := getTagsFromScenarioResult(scenarioResult)
+
+		key := scenarioKey{name: scenarioResult.Name, class: scenarioResult.Class}
+		if i, ok := index[key]; ok {
+			scenarios[i].Features = appendFeatures(scenarios[i].Features, features...)
+			scenarios[i].Tags = appendTags(scenarios[i].Tags, tags...)
+			continue
+		}
+
+		index[key] = len(scenarios)
 		scenarios = append(scenarios, Scenario{
 			Name:     scenarioResult.Name,
 			Class:    scenarioResult.Class,
 			TestType: d.SuiteResult.TestType,
 			Service:  d.SuiteResult.Service,
-			Features: getFeaturesFromScenarioResult(d.Jira, scenarioResult),
+			Features: appendFeatures(nil, features...),
+			Tags:     appendTags(nil, tags...),
 		})
 	}
 
-	return saveToDB(dbh, suiteResult, scenarios)
+	return scenarios
 }
 
 // saveToDB
 func saveToDB(dbh *storage.DBHandler, suiteResult *SuiteResult, scenarios []Scenario) error {
 	switch db := (*dbh).(type) {
 	case storage.Postgres:
-		return writeToPostgres(&db, suiteResult, scenarios)
+		return writeToDB(db.GetDB(), suiteResult, scenarios)
+	case storage.SQLite:
+		return writeToDB(db.GetDB(), suiteResult, scenarios)
+	case storage.MySQL:
+		if err := checkIndexedSizes(suiteResult, scenarios, storage.MySQLIndexedStringSize); err != nil {
+			return err
+		}
+
+		return writeToDB(db.GetDB(), suiteResult, scenarios)
 	}
 
 	return nil
 }
 
-// writeToPostgres
-func writeToPostgres(db *storage.Postgres, suiteResult *SuiteResult, scenarios []Scenario) error {
-	// Insert scenarios
-	if err := db.GetDB().Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "name"}, {Name: "class"}, {Name: "test_type"}, {Name: "service"}},
-		DoUpdates: clause.AssignmentColumns([]string{"name"}),
-	}).Create(&scenarios).Error; err != nil {
+// checkIndexedSizes returns an error when a value of a unique index or primary key column is longer than size
+// characters, which the DB would cut or reject. Scenarios which differ only after size characters could not be told apart otherwise
+func checkIndexedSizes(suiteResult *SuiteResult, scenarios []Scenario, size int) error {
+	values := [][2]string{{"build", suiteResult.Build}, {"test type", suiteResult.TestType}, {"service", suiteResult.Service}}
+	for _, scenario := range scenarios {
+		values = append(values, [2]string{"scenario", scenario.Name}, [2]string{"class", scenario.Class})
+		for _, feature := range scenario.Features {
+			values = append(values, [2]string{"feature", feature.ID})
+		}
+		for _, tag := range scenario.Tags {
+			values = append(values, [2]string{"tag", tag.ID})
+		}
+	}
+
+	for _, value := range values {
+		if utf8.RuneCountInString(value[1]) > size {
+			return fmt.Errorf(errValueTooLong, value[0], value[1], size)
+		}
+	}
+
+	return nil
+}
+
+// writeToDB inserts scenarios and suite result. Scenarios are inserted first and then read back by name and class,
+// before saving their features and tags, as ids returned on insert are not mapped to existing scenarios by every DB
+func writeToDB(db *gorm.DB, suiteResult *SuiteResult, scenarios []Scenario) error {
+	// Insert scenarios which are not saved yet
+	if err := db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&scenarios).Error; err != nil {
+		return err
+	}
+
+	if err := loadScenarioIDs(db, scenarios); err != nil {
+		return err
+	}
+
+	// Scenarios exist now, hence only features and tags are inserted
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&scenarios).Error; err != nil {
 		return err
 	}
 
 	// Update scenario results with scenario id
-	for i := range suiteResult.ScenarioResults {
-		suiteResult.ScenarioResults[i].ScenarioID = scenarios[i].ID
+	if err := setScenarioIDs(suiteResult, scenarios); err != nil {
+		return err
 	}
 
 	// Insert suiteResults
-	return db.GetDB().Create(suiteResult).Error
+	return db.Create(suiteResult).Error
+}
+
+// loadScenarioIDs sets ids of the saved scenarios, ids set on insert are not reliable when some scenarios exist
+func loadScenarioIDs(db *gorm.DB, scenarios []Scenario) error {
+	if len(scenarios) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		names = append(names, scenario.Name)
+	}
+
+	saved := make([]Scenario, 0, len(scenarios))
+	if err := db.Select("id", "name", "class").
+		Where("test_type = ? AND service = ? AND name IN ?", scenarios[0].TestType, scenarios[0].Service, names).
+		Find(&saved).Error; err != nil {
+		return err
+	}
+
+	ids := make(map[scenarioKey]uint, len(saved))
+	for _, scenario := range saved {
+		ids[scenarioKey{name: scenario.Name, class: scenario.Class}] = scenario.ID
+	}
+
+	for i := range scenarios {
+		scenarios[i].ID = ids[scenarioKey{name: scenarios[i].Name, class: scenarios[i].Class}]
+		if scenarios[i].ID == 0 {
+			return fmt.Errorf(errMissingScenarioID, scenarios[i].Name, scenarios[i].Class)
+		}
+	}
+
+	return nil
+}
+
+// setScenarioIDs sets id of the scenario with the same name and class on each scenario result and its metrics
+func setScenarioIDs(suiteResult *SuiteResult, scenarios []Scenario) error {
+	ids := make(map[scenarioKey]uint, len(scenarios))
+	for _, scenario := range scenarios {
+		ids[scenarioKey{name: scenario.Name, class: scenario.Class}] = scenario.ID
+	}
+
+	for i := range suiteResult.ScenarioResults {
+		scenarioResult := &suiteResult.ScenarioResults[i]
+
+		id := ids[scenarioKey{name: scenarioResult.Name, class: scenarioResult.Class}]
+		if id == 0 {
+			return fmt.Errorf(errMissingScenarioID, scenarioResult.Name, scenarioResult.Class)
+		}
+
+		scenarioResult.ScenarioID = id
+		for j := range scenarioResult.Metrics {
+			scenarioResult.Metrics[j].ScenarioID = id
+		}
+	}
+
+	return nil
 }
 
 // getFeaturesFromScenarioResult
@@ -137,3 +361,62 @@ func getFeaturesFromScenarioResult(projectName string, r ScenarioResult) []Featu
 
 	return features
 }
+
+// getTagsFromScenarioResult
+func getTagsFromScenarioResult(r ScenarioResult) []Tag {
+	tags := make([]Tag, 0, len(r.Tags))
+	for _, t := range r.Tags {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			tags = append(tags, Tag{ID: t})
+		}
+	}
+
+	return tags
+}
+
+// appendFeatures appends features which are not in the list yet
+func appendFeatures(features []Feature, add ...Feature) []Feature {
+	if features == nil {
+		features = make([]Feature, 0, len(add))
+	}
+
+	for _, f := range add {
+		exists := false
+		for _, existing := range features {
+			if existing.ID == f.ID {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			features = append(features, f)
+		}
+	}
+
+	return features
+}
+
+// appendTags appends tags which are not in the list yet
+func appendTags(tags []Tag, add ...Tag) []Tag {
+	if tags == nil {
+		tags = make([]Tag, 0, len(add))
+	}
+
+	for _, t := range add {
+		exists := false
+		for _, existing := range tags {
+			if existing.ID == t.ID {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			tags = append(tags, t)
+		}
+	}
+
+	return tags
+}
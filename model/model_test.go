@@ -1,6 +1,8 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"treco/storage"
 
@@ -76,3 +78,85 @@ func TestGetFeaturesFromScenarioResult(t *testing.T) {
 		require.ElementsMatch(t, data.featuresExtracted, features)
 	}
 }
+
+func TestGetTagsFromScenarioResult(t *testing.T) {
+	tags := getTagsFromScenarioResult(ScenarioResult{
+		Tags: []string{"smoke", " ", "regression "},
+	})
+
+	require.Equal(t, []Tag{{ID: "smoke"}, {ID: "regression"}}, tags)
+}
+
+func TestScenariosAreDeduplicated(t *testing.T) {
+	data := &Data{
+		Jira: "project",
+		SuiteResult: SuiteResult{
+			TestType: "unit",
+			Service:  "abc",
+			ScenarioResults: []ScenarioResult{
+				{Name: "test_login", Class: "module_a.LoginTest", Status: "failed", Features: []string{"project-1"}, Tags: []string{"smoke"}},
+				{Name: "test_login", Class: "module_b.LoginTest", Status: "passed"},
+				{Name: "test_login", Class: "module_a.LoginTest", Status: "passed", Features: []string{"project-2", "project-1"}, Tags: []string{"smoke", "login"}},
+			},
+		},
+	}
+
+	scenarios := data.scenarios()
+	require.Len(t, scenarios, 2)
+	require.Equal(t, "module_a.LoginTest", scenarios[0].Class)
+	require.Equal(t, []Feature{{ID: "PROJECT-1"}, {ID: "PROJECT-2"}}, scenarios[0].Features)
+	require.Equal(t, []Tag{{ID: "smoke"}, {ID: "login"}}, scenarios[0].Tags)
+	require.Equal(t, "module_b.LoginTest", scenarios[1].Class)
+	require.Empty(t, scenarios[1].Features)
+	require.Empty(t, scenarios[1].Tags)
+}
+
+func TestCheckIndexedSizes(t *testing.T) {
+	suiteResult := &SuiteResult{Build: "1", TestType: "unit", Service: "abc"}
+
+	// Size is in characters, not bytes
+	name := strings.Repeat("é", 191)
+	require.NoError(t, checkIndexedSizes(suiteResult, []Scenario{{Name: name, Class: "LoginTest"}}, 191))
+
+	err := checkIndexedSizes(suiteResult, []Scenario{{Name: "test_login", Class: "LoginTest"}, {Name: name + "a"}}, 191)
+	require.Equal(t, fmt.Errorf(errValueTooLong, "scenario", name+"a", 191), err)
+
+	tag := strings.Repeat("t", 192)
+	err = checkIndexedSizes(suiteResult, []Scenario{{Name: "test_login", Tags: []Tag{{ID: "smoke"}, {ID: tag}}}}, 191)
+	require.Equal(t, fmt.Errorf(errValueTooLong, "tag", tag, 191), err)
+
+	feature := "PROJECT-" + strings.Repeat("1", 184)
+	err = checkIndexedSizes(suiteResult, []Scenario{{Name: "test_login", Features: []Feature{{ID: feature}}}}, 191)
+	require.Equal(t, fmt.Errorf(errValueTooLong, "feature", feature, 191), err)
+
+	suiteResult.Service = strings.Repeat("s", 192)
+	err = checkIndexedSizes(suiteResult, nil, 191)
+	require.Equal(t, fmt.Errorf(errValueTooLong, "service", suiteResult.Service, 191), err)
+}
+
+func TestSetScenarioIDs(t *testing.T) {
+	suiteResult := &SuiteResult{
+		ScenarioResults: []ScenarioResult{
+			{Name: "test_login", Class: "module_a.LoginTest"},
+			{Name: "test_login", Class: "module_b.LoginTest", Metrics: []BenchmarkMetric{{Name: "time", Value: 1.5}}},
+			{Name: "test_login", Class: "module_a.LoginTest"},
+		},
+	}
+
+	// Order of scenarios is not the order of scenario results
+	scenarios := []Scenario{
+		{ID: 7, Name: "test_login", Class: "module_b.LoginTest"},
+		{ID: 3, Name: "test_login", Class: "module_a.LoginTest"},
+	}
+
+	err := setScenarioIDs(suiteResult, scenarios)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), suiteResult.ScenarioResults[0].ScenarioID)
+	require.Equal(t, uint(7), suiteResult.ScenarioResults[1].ScenarioID)
+	require.Equal(t, uint(7), suiteResult.ScenarioResults[1].Metrics[0].ScenarioID)
+	require.Equal(t, uint(3), suiteResult.ScenarioResults[2].ScenarioID)
+
+	err = setScenarioIDs(suiteResult, scenarios[:1])
+	require.Error(t, err)
+	require.Equal(t, fmt.Sprintf(errMissingScenarioID, "test_login", "module_a.LoginTest"), err.Error())
+}
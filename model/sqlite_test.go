@@ -0,0 +1,93 @@
+//go:build cgo
+
+package model
+
+import (
+	"path/filepath"
+	"testing"
+	"treco/conf"
+	"treco/migration"
+	"treco/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSaveToSQLite(t *testing.T) {
+	conf.Set(storage.DBType, "sqlite")
+	conf.Set(storage.DBName, filepath.Join(t.TempDir(), "treco.db"))
+	require.NoError(t, storage.New())
+
+	dbh := storage.Handler()
+	defer func() {
+		_ = (*dbh).Close()
+		*dbh = nil
+	}()
+
+	err := migration.Up((*dbh).GetDB())
+	require.NoError(t, err)
+
+	newData := func(build string) *Data {
+		return &Data{
+			Jira: "project",
+			SuiteResult: SuiteResult{
+				Build:       build,
+				TestType:    "performance",
+				Service:     "abc",
+				Environment: "test",
+				ScenarioResults: []ScenarioResult{
+					{Name: "BenchmarkParse", Class: "treco/report", Status: "passed", Tags: []string{"parser"},
+						Metrics: []BenchmarkMetric{{Name: "time", Value: 1200, Unit: "ns/op"}}},
+					{Name: "BenchmarkDetect", Class: "treco/report", Status: "passed"},
+					{Name: "BenchmarkParse", Class: "treco/report", Status: "passed", Features: []string{"project-1"},
+						Metrics: []BenchmarkMetric{{Name: "time", Value: 1100, Unit: "ns/op"}}},
+				},
+			},
+		}
+	}
+
+	db := (*dbh).(storage.SQLite).GetDB()
+
+	first := newData("1")
+	require.NoError(t, first.Save(dbh))
+
+	// Scenarios of a later build are the ones saved with the first build
+	second := newData("2")
+	require.NoError(t, second.Save(dbh))
+
+	var scenarios []Scenario
+	require.NoError(t, db.Preload("Features").Preload("Tags").Order("id").Find(&scenarios).Error)
+	require.Len(t, scenarios, 2)
+	require.Equal(t, "BenchmarkParse", scenarios[0].Name)
+	require.Len(t, scenarios[0].Features, 1)
+	require.Len(t, scenarios[0].Tags, 1)
+
+	for _, data := range []*Data{first, second} {
+		results := data.SuiteResult.ScenarioResults
+		require.Equal(t, scenarios[0].ID, results[0].ScenarioID)
+		require.Equal(t, scenarios[1].ID, results[1].ScenarioID)
+		require.Equal(t, scenarios[0].ID, results[2].ScenarioID)
+	}
+
+	var metrics []BenchmarkMetric
+	require.NoError(t, db.Order("id").Find(&metrics).Error)
+	require.Len(t, metrics, 4)
+	for _, metric := range metrics {
+		require.Equal(t, scenarios[0].ID, metric.ScenarioID)
+		require.NotZero(t, metric.ScenarioResultID)
+	}
+
+	// New scenario saved along with existing ones is mapped by name and class, not by the order of inserted rows
+	third := newData("3")
+	third.SuiteResult.ScenarioResults = append([]ScenarioResult{
+		{Name: "BenchmarkNew", Class: "treco/report", Status: "passed"},
+	}, third.SuiteResult.ScenarioResults...)
+	require.NoError(t, third.Save(dbh))
+
+	var created Scenario
+	require.NoError(t, db.Where("name = ?", "BenchmarkNew").First(&created).Error)
+
+	results := third.SuiteResult.ScenarioResults
+	require.Equal(t, created.ID, results[0].ScenarioID)
+	require.Equal(t, scenarios[0].ID, results[1].ScenarioID)
+	require.Equal(t, scenarios[1].ID, results[2].ScenarioID)
+}
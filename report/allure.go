@@ -0,0 +1,238 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"treco/model"
+)
+
+// AllureResult struct, contents of a single *-result.json file
+type AllureResult struct {
+	HistoryID  string            `json:"historyId"`
+	FullName   string            `json:"fullName"`
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Start      int64             `json:"start"`
+	Stop       int64             `json:"stop"`
+	Labels     []AllureLabel     `json:"labels"`
+	Links      []AllureLink      `json:"links"`
+	Parameters []AllureParameter `json:"parameters"`
+}
+
+// AllureLabel struct
+type AllureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// AllureLink struct
+type AllureLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+// AllureParameter struct
+type AllureParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+var (
+	errUnableToUnmarshalToAllure = "unmarshalling to allure result failed: %v"
+	errNoAllureResults           = "no allure results found in archive"
+
+	// Labels stored as scenario tags, other labels like host or thread are ignored
+	allureTagLabels = [...]string{"epic", "feature", "story"}
+)
+
+type allureParser struct{}
+
+func init() {
+	Register("allure", allureParser{})
+}
+
+func (allureParser) Parse(r io.Reader, result *model.Data) error {
+	results := newAllureResults()
+
+	log.Println("reading allure results archive")
+	err := readArchive(r, func(name string, f io.Reader) error {
+		if !isAllureResult(name) {
+			return nil
+		}
+
+		return results.add(name, f)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return results.addTo(&result.SuiteResult)
+}
+
+// allureResults collects result files, retried tests produce a result file per attempt, all sharing the same history id
+type allureResults struct {
+	attempts map[string][]AllureResult
+	order    []string
+}
+
+func newAllureResults() *allureResults {
+	return &allureResults{
+		attempts: make(map[string][]AllureResult),
+		order:    make([]string, 0),
+	}
+}
+
+// isAllureResult checks if the file is an allure result, other files in allure-results are containers or attachments
+func isAllureResult(name string) bool {
+	return strings.HasSuffix(path.Base(name), "-result.json")
+}
+
+// add decodes a result file
+func (a *allureResults) add(name string, f io.Reader) error {
+	ar := AllureResult{}
+	if err := json.NewDecoder(f).Decode(&ar); err != nil {
+		return fmt.Errorf(errUnableToUnmarshalToAllure, name)
+	}
+
+	key := ar.HistoryID
+	if key == "" {
+		key = ar.FullName + "#" + ar.Name
+	}
+
+	if _, ok := a.attempts[key]; !ok {
+		a.order = append(a.order, key)
+	}
+
+	a.attempts[key] = append(a.attempts[key], ar)
+	return nil
+}
+
+// addTo adds a scenario result for each test to suite result
+func (a *allureResults) addTo(suiteResult *model.SuiteResult) error {
+	if len(a.order) == 0 {
+		return fmt.Errorf(errNoAllureResults)
+	}
+
+	for _, key := range a.order {
+		results := a.attempts[key]
+
+		// Latest attempt decides the final status
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Stop < results[j].Stop
+		})
+
+		ar := results[len(results)-1]
+
+		status := allureStatus(ar)
+		if status == PASSED && len(results) > 1 && allureStatus(results[0]) == FAILED {
+			status = FLAKY
+		}
+
+		timeTaken := float64(ar.Stop-ar.Start) / 1000
+		suiteResult.TimeTaken += timeTaken
+
+		name := ar.Name
+		params := allureParamValues(ar.Parameters)
+		if params != "" {
+			name = fmt.Sprintf("%s[%s]", name, params)
+		}
+
+		AddScenarioResult(suiteResult, model.ScenarioResult{
+			Name:       name,
+			Class:      allureClass(ar),
+			Status:     status,
+			TimeTaken:  timeTaken,
+			Attempts:   uint(len(results)),
+			Features:   allureIssues(ar.Links),
+			Tags:       allureTags(ar.Labels),
+			Parameters: params,
+		})
+	}
+
+	return nil
+}
+
+// allureStatus maps status of the result, broken tests are failed
+func allureStatus(ar AllureResult) string {
+	switch strings.ToLower(ar.Status) {
+	case "failed", "broken":
+		return FAILED
+	case "skipped", "unknown":
+		return SKIPPED
+	}
+
+	return PASSED
+}
+
+// allureClass returns test class from labels, falling back to the full name without the test name
+func allureClass(ar AllureResult) string {
+	label := map[string]string{}
+	for _, l := range ar.Labels {
+		label[l.Name] = l.Value
+	}
+
+	if label["testClass"] != "" {
+		return label["testClass"]
+	}
+
+	if label["suite"] != "" {
+		return label["suite"]
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(ar.FullName, ar.Name), ".")
+}
+
+// allureIssues returns names of issue and tms links, or the last path element of link url if name is missing
+func allureIssues(links []AllureLink) []string {
+	issues := make([]string, 0)
+	for _, link := range links {
+		if link.Type != "issue" && link.Type != "tms" {
+			continue
+		}
+
+		name := link.Name
+		if name == "" {
+			name = path.Base(link.URL)
+		}
+
+		issues = append(issues, name)
+	}
+
+	return issues
+}
+
+// allureTags returns tag labels as is, and epic, feature and story labels as name=value
+func allureTags(labels []AllureLabel) []string {
+	tags := make([]string, 0)
+	for _, label := range labels {
+		if label.Name == "tag" {
+			tags = append(tags, label.Value)
+			continue
+		}
+
+		for _, l := range allureTagLabels {
+			if label.Name == l {
+				tags = append(tags, label.Name+"="+label.Value)
+			}
+		}
+	}
+
+	return tags
+}
+
+// allureParamValues joins parameter values as listed in the result
+func allureParamValues(params []AllureParameter) string {
+	values := make([]string, 0, len(params))
+	for _, param := range params {
+		values = append(values, param.Value)
+	}
+
+	return strings.Join(values, ", ")
+}
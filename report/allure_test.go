@@ -0,0 +1,116 @@
+package report
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+var allureTestResults = map[string]string{
+	"allure-results/1-result.json": `{
+		"historyId": "h1", "fullName": "com.app.LoginTest.testLogin", "name": "testLogin", "status": "failed",
+		"start": 1000, "stop": 2000,
+		"labels": [{"name": "testClass", "value": "com.app.LoginTest"}, {"name": "tag", "value": "smoke"}, {"name": "story", "value": "login"}, {"name": "host", "value": "ci-1"}],
+		"links": [{"name": "PROJ-1", "url": "https://jira/browse/PROJ-1", "type": "issue"}, {"url": "https://tms/case/PROJ-2", "type": "tms"}, {"name": "docs", "url": "https://docs", "type": "link"}]
+	}`,
+	"allure-results/2-result.json": `{
+		"historyId": "h1", "fullName": "com.app.LoginTest.testLogin", "name": "testLogin", "status": "passed",
+		"start": 3000, "stop": 4500,
+		"labels": [{"name": "testClass", "value": "com.app.LoginTest"}, {"name": "tag", "value": "smoke"}, {"name": "story", "value": "login"}],
+		"links": [{"name": "PROJ-1", "url": "https://jira/browse/PROJ-1", "type": "issue"}, {"url": "https://tms/case/PROJ-2", "type": "tms"}]
+	}`,
+	"allure-results/3-result.json": `{
+		"historyId": "h2", "fullName": "com.app.LoginTest.testBrowser", "name": "testBrowser", "status": "broken",
+		"start": 1000, "stop": 1500, "parameters": [{"name": "browser", "value": "chrome"}]
+	}`,
+	"allure-results/4-container.json": `{"uuid": "c1", "children": ["1", "2"]}`,
+}
+
+func TestInvalidAllureContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "allure",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnsupportedArchive), err)
+}
+
+// nolint: scopelint
+func TestAllureReportParsing(t *testing.T) {
+	archives := map[string][]byte{
+		"zip":    createTestZip(t, allureTestResults),
+		"tar.gz": createTestTarGz(t, allureTestResults),
+	}
+
+	for archiveType, archive := range archives {
+		t.Run(archiveType, func(t *testing.T) {
+			data := &model.Data{
+				ReportFormat: "allure",
+			}
+
+			err := Parse(bytes.NewReader(archive), data)
+			require.NoError(t, err, "Parsing error")
+			require.Equal(t, uint(2), data.SuiteResult.TotalExecuted)
+			require.Equal(t, uint(0), data.SuiteResult.TotalPassed)
+			require.Equal(t, uint(1), data.SuiteResult.TotalFlaky)
+			require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+			require.InDelta(t, 2.0, data.SuiteResult.TimeTaken, 0.0001)
+
+			results := map[string]model.ScenarioResult{}
+			for _, sr := range data.SuiteResult.ScenarioResults {
+				results[sr.Name] = sr
+			}
+
+			login := results["testLogin"]
+			require.Equal(t, "com.app.LoginTest", login.Class)
+			require.Equal(t, FLAKY, login.Status)
+			require.Equal(t, uint(2), login.Attempts)
+			require.Equal(t, []string{"PROJ-1", "PROJ-2"}, login.Features)
+			require.Equal(t, []string{"smoke", "story=login"}, login.Tags)
+
+			browser := results["testBrowser"]
+			require.Equal(t, "com.app.LoginTest", browser.Class)
+			require.Equal(t, FAILED, browser.Status)
+			require.Equal(t, uint(1), browser.Attempts)
+			require.Equal(t, "chrome", browser.Parameters)
+		})
+	}
+}
+
+func createTestZip(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func createTestTarGz(t *testing.T, files map[string]string) []byte {
+	return gzipTestData(t, tarTestData(t, files))
+}
+
+func tarTestData(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, contents := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(contents)), Typeflag: tar.TypeReg})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	errUnsupportedArchive = "unsupported archive, expected zip or tar.gz"
+
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte{0x1f, 0x8b}
+
+	// tar has no magic at the start, but "ustar" in the header of its first file
+	tarMagic       = []byte("ustar")
+	tarMagicOffset = 257
+)
+
+// isArchive checks if the reader holds a zip or tar archive, without consuming it.
+// Compressed data must be decompressed before the check
+func isArchive(br *bufio.Reader) bool {
+	head, _ := br.Peek(tarMagicOffset + len(tarMagic))
+	return bytes.HasPrefix(head, zipMagic) ||
+		(len(head) == tarMagicOffset+len(tarMagic) && bytes.Equal(head[tarMagicOffset:], tarMagic))
+}
+
+// readArchive calls fn for every regular file in a zip or tar archive, the archive can be gzip or zstd compressed
+func readArchive(r io.Reader, fn func(name string, r io.Reader) error) error {
+	br, err := decompress(bufio.NewReaderSize(r, detectPeekSize))
+	if err != nil {
+		return err
+	}
+
+	if !isArchive(br) {
+		return fmt.Errorf(errUnsupportedArchive)
+	}
+
+	magic, _ := br.Peek(len(zipMagic))
+	if bytes.HasPrefix(magic, zipMagic) {
+		return readZip(br, fn)
+	}
+
+	return readTar(br, fn)
+}
+
+// readZip needs random access, hence the archive is copied to a temporary file instead of being held in memory
+func readZip(r io.Reader, fn func(name string, r io.Reader) error) error {
+	tmp, err := os.CreateTemp("", "treco-*.zip")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if err := readZipFile(f, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readZipFile(f *zip.File, fn func(name string, r io.Reader) error) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	return fn(f.Name, limitDecompressedSize(rc))
+}
+
+func readTar(r io.Reader, fn func(name string, r io.Reader) error) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err := fn(header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+var archiveTestReports = map[string]string{
+	"surefire-reports/TEST-com.app.LoginTest.xml": `<?xml version="1.0" encoding="UTF-8"?>
+		<testsuite name="com.app.LoginTest" tests="2" failures="1" time="1.5">
+			<testcase name="testLogin" classname="com.app.LoginTest" time="0.5"/>
+			<testcase name="testLogout" classname="com.app.LoginTest" time="1"><failure/></testcase>
+		</testsuite>`,
+	"surefire-reports/TEST-com.app.HomeTest.xml": `<?xml version="1.0" encoding="UTF-8"?>
+		<testsuite name="com.app.HomeTest" tests="1" time="0.5">
+			<testcase name="testHome" classname="com.app.HomeTest" time="0.5"/>
+		</testsuite>`,
+	"surefire-reports/com.app.LoginTest.txt": "Tests run: 2, Failures: 1, Errors: 0, Skipped: 0",
+}
+
+// nolint: scopelint
+func TestParseArchiveOfReports(t *testing.T) {
+	archives := map[string][]byte{
+		"zip":    createTestZip(t, archiveTestReports),
+		"tar.gz": createTestTarGz(t, archiveTestReports),
+	}
+
+	for archiveType, archive := range archives {
+		for _, reportFormat := range []string{"", "junit"} {
+			t.Run(archiveType+" "+reportFormat, func(t *testing.T) {
+				data := &model.Data{
+					ReportFormat: reportFormat,
+				}
+
+				err := Parse(bytes.NewReader(archive), data)
+				require.NoError(t, err, "Parsing error")
+				require.Equal(t, "junit", data.SuiteResult.ReportFormat)
+				require.Equal(t, uint(3), data.SuiteResult.TotalExecuted)
+				require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+				require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+				require.InDelta(t, 2.0, data.SuiteResult.TimeTaken, 0.0001)
+				require.Equal(t, 3, len(data.SuiteResult.ScenarioResults))
+			})
+		}
+	}
+}
+
+func TestParseArchiveWithMixedFormats(t *testing.T) {
+	archive := createTestZip(t, map[string]string{
+		"junit.xml": archiveTestReports["surefire-reports/TEST-com.app.HomeTest.xml"],
+		"infra.tap": "TAP version 13\n1..1\nok 1 - disk is mounted\n",
+	})
+
+	data := &model.Data{}
+	err := Parse(bytes.NewReader(archive), data)
+	require.NoError(t, err, "Parsing error")
+	require.ElementsMatch(t, []string{"junit", "tap"}, strings.Split(data.SuiteResult.ReportFormat, ","))
+	require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+}
+
+func TestParseArchiveWithoutReports(t *testing.T) {
+	archive := createTestZip(t, map[string]string{
+		"readme.txt": "nothing to see",
+	})
+
+	data := &model.Data{}
+	err := Parse(bytes.NewReader(archive), data)
+	require.Equal(t, fmt.Errorf(errNoReportsInArchive), err)
+}
+
+// plainParser reads one passed test name per line, its format cannot be detected
+type plainParser struct{}
+
+func (plainParser) Parse(r io.Reader, result *model.Data) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Fields(string(b)) {
+		AddScenarioResult(&result.SuiteResult, model.ScenarioResult{Name: name, Status: PASSED})
+	}
+
+	return nil
+}
+
+func TestParseArchiveOfUndetectableFormat(t *testing.T) {
+	withParsers(t)
+	Register("plain", plainParser{})
+
+	archive := createTestZip(t, map[string]string{
+		"login.txt": "test_login\ntest_logout",
+		"home.txt":  "test_home",
+	})
+
+	data := &model.Data{ReportFormat: "plain"}
+	err := Parse(bytes.NewReader(archive), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(3), data.SuiteResult.TotalPassed)
+}
+
+func TestParseMultipleReports(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	for _, name := range []string{"surefire-reports/TEST-com.app.LoginTest.xml", "surefire-reports/TEST-com.app.HomeTest.xml"} {
+		err := Parse(bytes.NewReader([]byte(archiveTestReports[name])), data)
+		require.NoError(t, err, "Parsing error")
+	}
+
+	require.Equal(t, "junit", data.SuiteResult.ReportFormat)
+	require.Equal(t, uint(3), data.SuiteResult.TotalExecuted)
+	require.Equal(t, 3, len(data.SuiteResult.ScenarioResults))
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+)
+
+// CoberturaClass struct
+type CoberturaClass struct {
+	Name     string          `xml:"name,attr"`
+	Filename string          `xml:"filename,attr"`
+	Lines    []CoberturaLine `xml:"lines>line"`
+}
+
+// CoberturaLine struct
+type CoberturaLine struct {
+	Number            string `xml:"number,attr"`
+	Hits              uint64 `xml:"hits,attr"`
+	Branch            bool   `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr"`
+}
+
+var (
+	errUnableToUnmarshalToCobertura = "unmarshalling to cobertura failed"
+
+	// Condition coverage is written as e.g. 50% (1/2)
+	coberturaConditions = regexp.MustCompile(`\((\d+)/(\d+)\)`)
+)
+
+type coberturaXMLParser struct{}
+
+// parse decodes classes one at a time, lines of a class are also listed under its methods and are not read twice
+func (coberturaXMLParser) parse(r io.Reader, result *coverage) error {
+	log.Println("decoding cobertura coverage report")
+	decoder := xml.NewDecoder(r)
+	pkg := ""
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf(errUnableToUnmarshalToCobertura)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "package":
+			pkg = xmlAttr(start, "name")
+
+		case "class":
+			class := CoberturaClass{}
+			if err := decoder.DecodeElement(&class, &start); err != nil {
+				return fmt.Errorf(errUnableToUnmarshalToCobertura)
+			}
+
+			for _, line := range class.Lines {
+				covered, valid := line.conditions()
+				result.addLine(pkg, class.Filename, line.Number, 1, line.Hits, covered, valid)
+			}
+		}
+	}
+}
+
+// conditions returns covered and total branches of a branch line
+func (l CoberturaLine) conditions() (uint, uint) {
+	if !l.Branch {
+		return 0, 0
+	}
+
+	m := coberturaConditions.FindStringSubmatch(l.ConditionCoverage)
+	if m == nil {
+		return 0, 0
+	}
+
+	return parseUint(m[1]), parseUint(m[2])
+}
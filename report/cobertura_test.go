@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidCoberturaContent(t *testing.T) {
+	contents := `<coverage><packages><package name="app"><classes><class filename="app/a.py"><lines>`
+	err := ParseCoverage(&model.Data{}, bytes.NewReader([]byte(contents)))
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToCobertura), err)
+}
+
+func TestCoberturaReportParsing(t *testing.T) {
+	data := &model.Data{}
+
+	contents := `<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.6" branch-rate="0.5" version="1.9" timestamp="1187350905008">
+	<sources><source>/src</source></sources>
+	<packages>
+		<package name="app" line-rate="0.75" branch-rate="0.5">
+			<classes>
+				<class name="Calculator" filename="app/calculator.py" line-rate="0.75" branch-rate="0.5">
+					<methods>
+						<method name="add" signature="">
+							<lines><line number="2" hits="3"/></lines>
+						</method>
+					</methods>
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="3"/>
+						<line number="3" hits="3" branch="true" condition-coverage="50% (1/2)"/>
+						<line number="4" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+		<package name="app.util" line-rate="0">
+			<classes>
+				<class name="Strings" filename="app/util/strings.py">
+					<lines><line number="1" hits="0"/></lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`
+
+	err := ParseCoverage(data, bytes.NewReader([]byte(contents)))
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, 60.0, data.SuiteResult.Coverage)
+	require.Equal(t, 50.0, data.SuiteResult.BranchCoverage)
+	require.Equal(t, 2, len(data.SuiteResult.Packages))
+
+	pkg := data.SuiteResult.Packages[0]
+	require.Equal(t, "app", pkg.Name)
+	require.Equal(t, uint(3), pkg.LinesCovered)
+	require.Equal(t, uint(4), pkg.LinesValid)
+	require.Equal(t, uint(1), pkg.BranchesCovered)
+	require.Equal(t, uint(2), pkg.BranchesValid)
+	require.Equal(t, 75.0, pkg.LineCoverage)
+	require.Equal(t, "app/calculator.py", pkg.Files[0].Name)
+	require.Equal(t, 75.0, pkg.Files[0].LineCoverage)
+
+	require.Equal(t, "app.util", data.SuiteResult.Packages[1].Name)
+	require.Equal(t, 0.0, data.SuiteResult.Packages[1].LineCoverage)
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"treco/conf"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported content encodings
+const (
+	GZIP = "gzip"
+	ZSTD = "zstd"
+)
+
+// MaxDecompressedSize is the environment variable for maximum bytes read from a decompressed report or archived file
+const MaxDecompressedSize = "MAX_DECOMPRESSED_SIZE"
+
+const defaultMaxDecompressedSize = 512 * 1024 * 1024
+
+var (
+	errUnsupportedEncoding      = "unsupported content encoding %v, expected gzip or zstd"
+	errDecompressedSizeExceeded = "decompressed report is larger than %v bytes, see " + MaxDecompressedSize
+
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Decompress wraps the reader with a decoder for the content encoding, reader is returned as is when encoding is empty
+// or identity
+func Decompress(r io.Reader, encoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return r, nil
+	case GZIP, "x-gzip":
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return limitDecompressedSize(gr), nil
+	case ZSTD:
+		zr, err := newZstdReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return limitDecompressedSize(zr), nil
+	default:
+		return nil, fmt.Errorf(errUnsupportedEncoding, encoding)
+	}
+}
+
+// decompress unwraps gzip and zstd compressed data based on magic bytes, data which is not compressed is returned as is
+func decompress(br *bufio.Reader) (*bufio.Reader, error) {
+	for {
+		magic, _ := br.Peek(len(zstdMagic))
+
+		var r io.Reader
+		var err error
+
+		switch {
+		case bytes.HasPrefix(magic, gzipMagic):
+			r, err = gzip.NewReader(br)
+		case bytes.HasPrefix(magic, zstdMagic):
+			r, err = newZstdReader(br)
+		default:
+			return br, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		br = bufio.NewReaderSize(limitDecompressedSize(r), detectPeekSize)
+	}
+}
+
+// newZstdReader decodes synchronously, so that no goroutines are left behind without closing the decoder
+func newZstdReader(r io.Reader) (io.Reader, error) {
+	return zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+}
+
+// decompressedSizeLimit returns the configured maximum decompressed size, falling back to the default when not set or
+// invalid
+func decompressedSizeLimit() int64 {
+	limit, err := strconv.ParseInt(conf.Get(MaxDecompressedSize), 10, 64)
+	if err != nil || limit <= 0 {
+		return defaultMaxDecompressedSize
+	}
+
+	return limit
+}
+
+// limitDecompressedSize caps the bytes read from decompressed data, so that a small compressed report can not exhaust
+// memory or disk. Unlike io.LimitReader, reading past the cap fails instead of ending the data silently
+func limitDecompressedSize(r io.Reader) io.Reader {
+	limit := decompressedSizeLimit()
+	return &sizeLimitedReader{r: io.LimitReader(r, limit+1), remaining: limit, limit: limit}
+}
+
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+	limit     int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf(errDecompressedSizeExceeded, l.limit)
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		// Underlying reader stops one byte past the cap, which is not returned
+		return n + int(l.remaining), fmt.Errorf(errDecompressedSizeExceeded, l.limit)
+	}
+
+	return n, err
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"testing"
+	"treco/conf"
+	"treco/model"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+var compressTestReport = `<testsuite name="suite" tests="2" failures="1" time="1">
+	<testcase name="test_passed" classname="suite" time="0.5"/>
+	<testcase name="test_failed" classname="suite" time="0.5"><failure/></testcase>
+</testsuite>`
+
+// nolint: scopelint
+func TestParseCompressedReports(t *testing.T) {
+	reports := map[string][]byte{
+		"gzip":        gzipTestData(t, []byte(compressTestReport)),
+		"zstd":        zstdTestData(t, []byte(compressTestReport)),
+		"zstd tar":    zstdTestData(t, tarTestData(t, map[string]string{"report.xml": compressTestReport})),
+		"zip of gzip": createTestZip(t, map[string]string{"report.xml.gz": string(gzipTestData(t, []byte(compressTestReport)))}),
+	}
+
+	for name, contents := range reports {
+		t.Run(name, func(t *testing.T) {
+			data := &model.Data{}
+
+			err := Parse(bytes.NewReader(contents), data)
+			require.NoError(t, err, "Parsing error")
+			require.Equal(t, "junit", data.SuiteResult.ReportFormat)
+			require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+			require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+		})
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	for _, encoding := range []string{GZIP, ZSTD, "", "identity"} {
+		contents := []byte(compressTestReport)
+		switch encoding {
+		case GZIP:
+			contents = gzipTestData(t, contents)
+		case ZSTD:
+			contents = zstdTestData(t, contents)
+		}
+
+		r, err := Decompress(bytes.NewReader(contents), encoding)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, compressTestReport, string(b))
+	}
+
+	_, err := Decompress(bytes.NewReader([]byte{}), "br")
+	require.Equal(t, fmt.Errorf(errUnsupportedEncoding, "br"), err)
+}
+
+// nolint: scopelint
+func TestDecompressedSizeLimit(t *testing.T) {
+	conf.Set(MaxDecompressedSize, "100")
+	defer conf.Set(MaxDecompressedSize, "")
+
+	reports := map[string][]byte{
+		"gzip":   gzipTestData(t, []byte(compressTestReport)),
+		"zstd":   zstdTestData(t, []byte(compressTestReport)),
+		"zipped": createTestZip(t, map[string]string{"report.xml": compressTestReport}),
+	}
+
+	for name, contents := range reports {
+		t.Run(name, func(t *testing.T) {
+			err := Parse(bytes.NewReader(contents), &model.Data{})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), fmt.Sprintf(errDecompressedSizeExceeded, 100))
+		})
+	}
+
+	r, err := Decompress(bytes.NewReader(gzipTestData(t, []byte(compressTestReport))), GZIP)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(r)
+	require.Equal(t, fmt.Errorf(errDecompressedSizeExceeded, 100), err)
+	require.Len(t, b, 100)
+}
+
+func gzipTestData(t *testing.T, contents []byte) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	_, err := gw.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func zstdTestData(t *testing.T, contents []byte) []byte {
+	zw, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	return zw.EncodeAll(contents, nil)
+}
@@ -0,0 +1,203 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"treco/model"
+)
+
+var (
+	errUnableToDetectCoverageFormat = "unable to detect coverage format, expected cobertura, jacoco, lcov or go coverprofile"
+	errNoCoverage                   = "no coverage found in coverage reports"
+
+	// XML root elements of coverage reports
+	xmlRootCoverageFormats = map[string]string{
+		"coverage": "cobertura",
+		"report":   "jacoco",
+	}
+)
+
+// coverageParser interface
+type coverageParser interface {
+	parse(r io.Reader, result *coverage) error
+}
+
+// ParseCoverage parses coverage reports and sets line and branch coverage of the suite result, with a breakdown per
+// package and file. Coverage of a file which is present in more than one report is merged
+func ParseCoverage(data *model.Data, files ...io.Reader) error {
+	result := newCoverage()
+	for _, f := range files {
+		if err := parseCoverageReport(f, result); err != nil {
+			return err
+		}
+	}
+
+	if len(result.order) == 0 {
+		return fmt.Errorf(errNoCoverage)
+	}
+
+	result.addTo(&data.SuiteResult)
+	return nil
+}
+
+// parseCoverageReport parses a single coverage report with the detected format
+func parseCoverageReport(r io.Reader, result *coverage) error {
+	var parser coverageParser
+
+	br, err := decompress(bufio.NewReaderSize(r, detectPeekSize))
+	if err != nil {
+		return err
+	}
+
+	switch detectCoverageFormat(br) {
+	case "cobertura":
+		parser = coberturaXMLParser{}
+	case "jacoco":
+		parser = jacocoXMLParser{}
+	case "lcov":
+		parser = lcovParser{}
+	case "gocover":
+		parser = goCoverProfileParser{}
+	default:
+		return fmt.Errorf(errUnableToDetectCoverageFormat)
+	}
+
+	return parser.parse(br, result)
+}
+
+// detectCoverageFormat sniffs the beginning of the coverage report to find its format without consuming the reader
+func detectCoverageFormat(br *bufio.Reader) string {
+	head, _ := br.Peek(detectPeekSize)
+	head = bytes.TrimSpace(bytes.TrimPrefix(head, utf8BOM))
+
+	switch {
+	case bytes.HasPrefix(head, []byte("<")):
+		return xmlRootCoverageFormats[xmlRootElement(head)]
+	case bytes.HasPrefix(head, []byte("mode:")):
+		return "gocover"
+	case bytes.HasPrefix(head, []byte("TN:")), bytes.HasPrefix(head, []byte("SF:")):
+		return "lcov"
+	}
+
+	return ""
+}
+
+// coverage collects hits and branches of each line of source files, grouped by package
+type coverage struct {
+	packages map[string][]string
+	files    map[string]map[string]*coverageLine
+	order    []string
+}
+
+// coverageLine is a line, or a block of statements for go coverprofile, of a source file
+type coverageLine struct {
+	statements      uint
+	hits            uint64
+	branchesCovered uint
+	branchesValid   uint
+}
+
+func newCoverage() *coverage {
+	return &coverage{
+		packages: make(map[string][]string),
+		files:    make(map[string]map[string]*coverageLine),
+		order:    make([]string, 0),
+	}
+}
+
+// addLine adds a line of a file, hits and branches of a line found in more than one report are merged
+func (c *coverage) addLine(pkg, file, line string, statements uint, hits uint64, branchesCovered, branchesValid uint) {
+	if _, ok := c.packages[pkg]; !ok {
+		c.order = append(c.order, pkg)
+	}
+
+	key := pkg + "\x00" + file
+	lines, ok := c.files[key]
+	if !ok {
+		lines = make(map[string]*coverageLine)
+		c.files[key] = lines
+		c.packages[pkg] = append(c.packages[pkg], file)
+	}
+
+	l, ok := lines[line]
+	if !ok {
+		l = &coverageLine{statements: statements}
+		lines[line] = l
+	}
+
+	l.hits += hits
+	if branchesValid > l.branchesValid {
+		l.branchesValid = branchesValid
+	}
+
+	if branchesCovered > l.branchesCovered {
+		l.branchesCovered = branchesCovered
+	}
+}
+
+// addTo sets package and file coverage on suite result, along with the coverage of the whole suite
+func (c *coverage) addTo(suiteResult *model.SuiteResult) {
+	var linesCovered, linesValid, branchesCovered, branchesValid uint
+
+	suiteResult.Packages = make([]model.PackageCoverage, 0, len(c.order))
+	for _, pkg := range c.order {
+		pc := model.PackageCoverage{Name: pkg}
+		for _, file := range c.packages[pkg] {
+			fc := model.FileCoverage{Name: file}
+			for _, l := range c.files[pkg+"\x00"+file] {
+				fc.LinesValid += l.statements
+				if l.hits > 0 {
+					fc.LinesCovered += l.statements
+				}
+
+				fc.BranchesValid += l.branchesValid
+				fc.BranchesCovered += l.branchesCovered
+			}
+
+			fc.LineCoverage = percentage(fc.LinesCovered, fc.LinesValid)
+			fc.BranchCoverage = percentage(fc.BranchesCovered, fc.BranchesValid)
+
+			pc.LinesCovered += fc.LinesCovered
+			pc.LinesValid += fc.LinesValid
+			pc.BranchesCovered += fc.BranchesCovered
+			pc.BranchesValid += fc.BranchesValid
+			pc.Files = append(pc.Files, fc)
+		}
+
+		pc.LineCoverage = percentage(pc.LinesCovered, pc.LinesValid)
+		pc.BranchCoverage = percentage(pc.BranchesCovered, pc.BranchesValid)
+
+		linesCovered += pc.LinesCovered
+		linesValid += pc.LinesValid
+		branchesCovered += pc.BranchesCovered
+		branchesValid += pc.BranchesValid
+		suiteResult.Packages = append(suiteResult.Packages, pc)
+	}
+
+	suiteResult.Coverage = percentage(linesCovered, linesValid)
+	suiteResult.BranchCoverage = percentage(branchesCovered, branchesValid)
+}
+
+// xmlAttr returns value of the attribute, empty when not set
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// percentage rounded to two decimals, zero when there is nothing to cover
+func percentage(covered, valid uint) float64 {
+	if valid == 0 {
+		return 0
+	}
+
+	return math.Round(float64(covered)/float64(valid)*10000) / 100
+}
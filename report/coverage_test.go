@@ -0,0 +1,56 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCoverageFormat(t *testing.T) {
+	reports := map[string]string{
+		"cobertura": `<?xml version="1.0" ?><coverage line-rate="1"></coverage>`,
+		"jacoco":    `<?xml version="1.0"?><!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd"><report name="a"/>`,
+		"lcov":      "TN:\nSF:a.js\n",
+		"gocover":   "mode: set\n",
+		"":          `<testsuite name="suite"/>`,
+	}
+
+	for format, contents := range reports {
+		br := bufio.NewReader(strings.NewReader(contents))
+		require.Equal(t, format, detectCoverageFormat(br))
+	}
+}
+
+func TestInvalidCoverageContent(t *testing.T) {
+	err := ParseCoverage(&model.Data{}, bytes.NewReader([]byte("test")))
+	require.Equal(t, fmt.Errorf(errUnableToDetectCoverageFormat), err)
+
+	err = ParseCoverage(&model.Data{}, bytes.NewReader([]byte("mode: set\n")))
+	require.Equal(t, fmt.Errorf(errNoCoverage), err)
+}
+
+func TestCoverageOfMultipleReports(t *testing.T) {
+	data := &model.Data{
+		SuiteResult: model.SuiteResult{
+			Coverage: 10,
+		},
+	}
+
+	// Same file covered by unit and integration tests, and compressed
+	unit := "SF:src/a.js\nDA:1,1\nDA:2,0\nBRDA:2,0,0,0\nBRDA:2,0,1,0\nend_of_record\n"
+	integration := "SF:src/a.js\nDA:1,0\nDA:2,3\nBRDA:2,0,0,3\nBRDA:2,0,1,0\nend_of_record\nSF:src/b.js\nDA:1,0\nend_of_record\n"
+
+	err := ParseCoverage(data, strings.NewReader(unit), bytes.NewReader(gzipTestData(t, []byte(integration))))
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, 66.67, data.SuiteResult.Coverage)
+	require.Equal(t, 50.0, data.SuiteResult.BranchCoverage)
+	require.Equal(t, 1, len(data.SuiteResult.Packages))
+	require.Equal(t, 2, len(data.SuiteResult.Packages[0].Files))
+	require.Equal(t, 100.0, data.SuiteResult.Packages[0].Files[0].LineCoverage)
+	require.Equal(t, 0.0, data.SuiteResult.Packages[0].Files[1].LineCoverage)
+}
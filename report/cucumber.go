@@ -0,0 +1,143 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"treco/model"
+)
+
+// CucumberFeature struct
+type CucumberFeature struct {
+	URI      string            `json:"uri"`
+	Name     string            `json:"name"`
+	Tags     []CucumberTag     `json:"tags"`
+	Elements []CucumberElement `json:"elements"`
+}
+
+// CucumberElement struct, either a scenario or a background
+type CucumberElement struct {
+	Name   string         `json:"name"`
+	Type   string         `json:"type"`
+	Tags   []CucumberTag  `json:"tags"`
+	Before []CucumberStep `json:"before"`
+	Steps  []CucumberStep `json:"steps"`
+	After  []CucumberStep `json:"after"`
+}
+
+// CucumberTag struct
+type CucumberTag struct {
+	Name string `json:"name"`
+}
+
+// CucumberStep struct, also used for hooks
+type CucumberStep struct {
+	Name   string         `json:"name"`
+	Result CucumberResult `json:"result"`
+}
+
+// CucumberResult struct, duration is in nanoseconds
+type CucumberResult struct {
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration"`
+}
+
+var (
+	errUnableToUnmarshalToCucumber = "unmarshalling to cucumber failed"
+)
+
+type cucumberJSONParser struct{}
+
+func init() {
+	Register("cucumber", cucumberJSONParser{})
+}
+
+func (cucumberJSONParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	features := make([]CucumberFeature, 0)
+
+	log.Println("unmarshalling to cucumber report")
+	if err := json.NewDecoder(r).Decode(&features); err != nil {
+		return fmt.Errorf(errUnableToUnmarshalToCucumber)
+	}
+
+	for _, feature := range features {
+		// Background steps are reported as a separate element preceding each scenario
+		var background []CucumberStep
+
+		for _, element := range feature.Elements {
+			if element.Type == "background" {
+				background = element.Steps
+				continue
+			}
+
+			steps := make([]CucumberStep, 0, len(element.Before)+len(background)+len(element.Steps)+len(element.After))
+			steps = append(steps, element.Before...)
+			steps = append(steps, background...)
+			steps = append(steps, element.Steps...)
+			steps = append(steps, element.After...)
+			background = nil
+
+			status, timeTaken := cucumberScenarioStatus(steps)
+
+			suiteResult.TimeTaken += timeTaken
+			tags := cucumberTagNames(feature.Tags, element.Tags)
+
+			AddScenarioResult(suiteResult, model.ScenarioResult{
+				Name:      element.Name,
+				Class:     feature.Name,
+				Status:    status,
+				TimeTaken: timeTaken,
+				Features:  tags,
+				Tags:      tags,
+			})
+		}
+	}
+
+	return nil
+}
+
+// cucumberScenarioStatus derives scenario status and time taken (in seconds) from its steps.
+// Any failed step fails the scenario, otherwise pending, undefined or skipped steps skip it
+func cucumberScenarioStatus(steps []CucumberStep) (string, float64) {
+	status := PASSED
+	var duration float64
+
+	for _, step := range steps {
+		duration += step.Result.Duration
+
+		switch strings.ToLower(step.Result.Status) {
+		case "failed", "ambiguous":
+			status = FAILED
+		case "skipped", "pending", "undefined":
+			if status == PASSED {
+				status = SKIPPED
+			}
+		}
+	}
+
+	return status, duration / 1e9
+}
+
+// cucumberTagNames merges feature and scenario tags, stripping the leading '@'
+func cucumberTagNames(tagSets ...[]CucumberTag) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+
+	for _, tags := range tagSets {
+		for _, tag := range tags {
+			name := strings.TrimPrefix(tag.Name, "@")
+			if name == "" || seen[name] {
+				continue
+			}
+
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidCucumberContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "cucumber",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToCucumber), err)
+}
+
+func TestCucumberReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "cucumber",
+	}
+
+	contents := `
+	[
+		{
+			"uri": "features/login.feature",
+			"name": "Login",
+			"tags": [{"name": "@PROJ-1"}],
+			"elements": [
+				{
+					"name": "",
+					"type": "background",
+					"steps": [{"name": "app is open", "result": {"status": "passed", "duration": 500000000}}]
+				},
+				{
+					"name": "valid login",
+					"type": "scenario",
+					"tags": [{"name": "@PROJ-1"}, {"name": "@PROJ-123"}],
+					"steps": [
+						{"name": "user logs in", "result": {"status": "passed", "duration": 1000000000}},
+						{"name": "home is shown", "result": {"status": "passed", "duration": 500000000}}
+					]
+				},
+				{
+					"name": "invalid login",
+					"type": "scenario",
+					"steps": [
+						{"name": "user logs in", "result": {"status": "failed", "duration": 1000000000}},
+						{"name": "error is shown", "result": {"status": "skipped"}}
+					]
+				},
+				{
+					"name": "forgot password",
+					"type": "scenario",
+					"steps": [{"name": "user resets password", "result": {"status": "undefined"}}]
+				}
+			]
+		}
+	]
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(3), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 3.0, data.SuiteResult.TimeTaken, 0.0001)
+	require.Equal(t, 3, len(data.SuiteResult.ScenarioResults))
+
+	validLogin := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "valid login", validLogin.Name)
+	require.Equal(t, "Login", validLogin.Class)
+	require.Equal(t, PASSED, validLogin.Status)
+	require.InDelta(t, 2.0, validLogin.TimeTaken, 0.0001)
+	require.Equal(t, []string{"PROJ-1", "PROJ-123"}, validLogin.Features)
+
+	require.Equal(t, FAILED, data.SuiteResult.ScenarioResults[1].Status)
+	require.Equal(t, SKIPPED, data.SuiteResult.ScenarioResults[2].Status)
+}
@@ -0,0 +1,182 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+)
+
+// Bytes peeked from the report to detect its format
+const detectPeekSize = 64 * 1024
+
+var (
+	errUnableToDetectReportFormat = "unable to detect report format, please set the report format explicitly"
+
+	utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+	// XML root elements and the formats they belong to
+	xmlRootFormats = map[string]string{
+		"testsuites":     "junit",
+		"testsuite":      "junit",
+		"testng-results": "testng",
+		"test-run":       "nunit3",
+		"assemblies":     "xunit",
+		"TestRun":        "trx",
+	}
+
+	// JSON keys found only in a format, go test events are new line delimited hence only the first one is read
+	jsonKeyFormats = map[string]string{
+		"Action":      "gotest",
+		"testResults": "jest",
+		"suites":      "playwright",
+	}
+
+	// JSON keys of the first element of a json array
+	jsonArrayKeyFormats = map[string]string{
+		"jmhVersion":    "jmh",
+		"primaryMetric": "jmh",
+		"elements":      "cucumber",
+		"uri":           "cucumber",
+	}
+
+	goBenchFirstLine = regexp.MustCompile(`^((goos|goarch|pkg|cpu): |Benchmark\S*\s+\d+\s)`)
+
+	tapFirstLine = regexp.MustCompile(`^(TAP version \d+|\d+\.\.\d+|(not )?ok\b)`)
+)
+
+// detectFormat sniffs the beginning of the report to find its format without consuming the reader, registered
+// parsers are asked when the format is none of the built in ones. Empty string is returned when format cannot be
+// detected
+func detectFormat(br *bufio.Reader) string {
+	head, _ := br.Peek(detectPeekSize)
+	head = bytes.TrimSpace(bytes.TrimPrefix(head, utf8BOM))
+	if len(head) == 0 {
+		return ""
+	}
+
+	if format := detectBuiltInFormat(head); format != "" {
+		return format
+	}
+
+	return detectRegisteredFormat(head)
+}
+
+// detectBuiltInFormat finds format of reports parsed by this package
+func detectBuiltInFormat(head []byte) string {
+	switch head[0] {
+	case '<':
+		return detectXMLFormat(head)
+	case '[':
+		return detectJSONArrayFormat(head)
+	case '{':
+		return detectJSONFormat(head)
+	}
+
+	if goBenchFirstLine.Match(head) {
+		return "gobench"
+	}
+
+	if tapFirstLine.Match(head) {
+		return "tap"
+	}
+
+	return detectGoTestEvents(head)
+}
+
+// detectGoTestEvents finds go test events after lines which are not json, e.g. build output of a package
+func detectGoTestEvents(head []byte) string {
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if bytes.HasPrefix(line, []byte("{")) {
+			if format := detectJSONFormat(line); format == "gotest" {
+				return format
+			}
+
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// isBuiltInDetectable checks if detectBuiltInFormat can find the format
+func isBuiltInDetectable(format string) bool {
+	// Allure results are found by their file names in archives
+	if format == "gobench" || format == "tap" || format == "allure" {
+		return true
+	}
+
+	for _, formats := range []map[string]string{xmlRootFormats, jsonKeyFormats, jsonArrayKeyFormats} {
+		for _, f := range formats {
+			if f == format {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// detectXMLFormat finds format from the root element
+func detectXMLFormat(head []byte) string {
+	return xmlRootFormats[xmlRootElement(head)]
+}
+
+// xmlRootElement returns local name of the root element, empty when head is not xml
+func xmlRootElement(head []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(head))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+// detectJSONFormat finds format from the keys of first json object
+func detectJSONFormat(head []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(head))
+	return jsonObjectFormat(decoder, jsonKeyFormats)
+}
+
+// detectJSONArrayFormat finds format from the keys of the first element of a json array, other arrays are left to
+// registered parsers
+func detectJSONArrayFormat(head []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(head))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return ""
+	}
+
+	return jsonObjectFormat(decoder, jsonArrayKeyFormats)
+}
+
+// jsonObjectFormat reads the next json object until a key of a format is found, values are skipped without decoding them
+func jsonObjectFormat(decoder *json.Decoder, keyFormats map[string]string) string {
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return ""
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		if key, ok := token.(string); ok && keyFormats[key] != "" {
+			return keyFormats[key]
+		}
+
+		value := json.RawMessage{}
+		if err := decoder.Decode(&value); err != nil {
+			return ""
+		}
+	}
+
+	return ""
+}
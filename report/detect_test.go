@@ -0,0 +1,78 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nolint: scopelint
+func TestDetectFormat(t *testing.T) {
+	testData := []struct {
+		format   string
+		contents []byte
+	}{
+		{"junit", []byte(`<?xml version="1.0"?><!-- generated --><testsuites><testsuite/></testsuites>`)},
+		{"junit", []byte("\xef\xbb\xbf\n<testsuite name=\"testsuites\"></testsuite>")},
+		{"testng", []byte(`<testng-results total="0"></testng-results>`)},
+		{"nunit3", []byte(`<?xml version="1.0"?><test-run></test-run>`)},
+		{"xunit", []byte(`<assemblies></assemblies>`)},
+		{"trx", []byte(`<TestRun xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010"></TestRun>`)},
+		{"cucumber", []byte(` [{"uri": "features/a.feature", "elements": []}]`)},
+		{"gotest", []byte(`{"Action":"start","Package":"treco/report"}` + "\n" + `{"Action":"pass"}`)},
+		{"jest", []byte(`{"numFailedTests":0,"snapshot":{"added":0},"testResults":[]}`)},
+		{"playwright", []byte("{\n  \"config\": {\n    \"projects\": [{\"name\": \"chromium\"}]\n  },\n  \"suites\": []\n}")},
+		{"jmh", []byte(`[{"jmhVersion": "1.36", "benchmark": "org.sample.MyBenchmark.measure", "mode": "thrpt"}]`)},
+		{"cucumber", []byte(`[{"keyword": "Feature", "elements": [{"name": "login"}], "uri": "a.feature"}]`)},
+		{"gotest", []byte("# treco/report\nbuild output\n" + `{"Time":"2023-01-01T00:00:00Z","Action":"start"}`)},
+		{"", []byte("# treco/report\n" + `{"unknown": true}`)},
+		{"", []byte(`[]`)},
+		{"", []byte(`[{"id": 1, "name": "other"}]`)},
+		{"gobench", []byte("goos: linux\ngoarch: amd64\npkg: treco/report\nBenchmarkParse-8   100   1234 ns/op\n")},
+		{"gobench", []byte("BenchmarkParse-8   100   1234 ns/op\nPASS\n")},
+		{"tap", []byte("TAP version 13\n1..1\nok 1\n")},
+		{"tap", []byte("1..2\nok 1\nok 2\n")},
+		{"", []byte(`{"unknown": true}`)},
+		{"", []byte(`<html></html>`)},
+		{"", []byte("some text")},
+		{"", []byte{}},
+	}
+
+	for _, data := range testData {
+		t.Run(fmt.Sprintf("%v %q", data.format, data.contents[:len(data.contents)/4]), func(t *testing.T) {
+			require.Equal(t, data.format, detectFormat(bufio.NewReaderSize(bytes.NewReader(data.contents), detectPeekSize)))
+		})
+	}
+}
+
+func TestParseWithDetectedFormat(t *testing.T) {
+	data := &model.Data{}
+
+	contents := `<testsuite tests="1"><testcase name="test_passed" classname="some.test.Class"/></testsuite>`
+	err := Parse(strings.NewReader(contents), data)
+	require.NoError(t, err)
+	require.Equal(t, "junit", data.SuiteResult.ReportFormat)
+	require.Equal(t, 1, len(data.SuiteResult.ScenarioResults))
+}
+
+func TestParseWithUndetectableFormat(t *testing.T) {
+	data := &model.Data{}
+
+	err := Parse(strings.NewReader("test"), data)
+	require.Equal(t, fmt.Errorf(errUnableToDetectReportFormat), err)
+}
+
+func TestParseWithMismatchedFormat(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "cucumber",
+	}
+
+	contents := `<testsuite tests="1"><testcase name="test_passed" classname="some.test.Class"/></testsuite>`
+	err := Parse(strings.NewReader(contents), data)
+	require.Equal(t, fmt.Errorf(errReportFormatMismatch, fmt.Errorf(errUnableToUnmarshalToCucumber), "junit", "cucumber"), err)
+}
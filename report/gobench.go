@@ -0,0 +1,125 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"treco/model"
+)
+
+var (
+	errUnableToReadGoBench = "reading go benchmark output failed, no benchmark results found"
+
+	// BenchmarkName-8   1000   1234 ns/op   56 B/op   7 allocs/op, GOMAXPROCS suffix is not part of the name
+	goBenchResultLine = regexp.MustCompile(`^(Benchmark\S*?)(?:-\d+)?\s+(\d+)\s+(.+)$`)
+	goBenchStatusLine = regexp.MustCompile(`^--- (FAIL|SKIP): (Benchmark\S*?)(?:-\d+)?$`)
+	goBenchPkgLine    = regexp.MustCompile(`^(ok|FAIL)\s+(\S+)\s+([\d.]+)s`)
+
+	// Names of the metrics reported by the testing package, custom metrics keep their unit as the name
+	goBenchMetricNames = map[string]string{
+		"ns/op":     "time",
+		"B/op":      "memory",
+		"allocs/op": "allocations",
+		"MB/s":      "throughput",
+	}
+)
+
+type goBenchParser struct{}
+
+func init() {
+	Register("gobench", goBenchParser{})
+}
+
+func (goBenchParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	pkg := ""
+	benchmarks := make([]model.ScenarioResult, 0)
+
+	log.Println("reading go benchmark output")
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), detectPeekSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "pkg:"):
+			pkg = strings.TrimSpace(strings.TrimPrefix(line, "pkg:"))
+
+		case goBenchResultLine.MatchString(line):
+			m := goBenchResultLine.FindStringSubmatch(line)
+			iterations, _ := strconv.ParseFloat(m[2], 64)
+			metrics := goBenchMetrics(m[3])
+
+			timeTaken := 0.0
+			for _, metric := range metrics {
+				if metric.Unit == "ns/op" {
+					timeTaken = metric.Value * iterations / 1e9
+				}
+			}
+
+			benchmarks = append(benchmarks, model.ScenarioResult{
+				Name:      m[1],
+				Class:     pkg,
+				Status:    PASSED,
+				TimeTaken: timeTaken,
+				Metrics:   metrics,
+			})
+
+		case goBenchStatusLine.MatchString(line):
+			// Failed or skipped benchmarks report no result line
+			m := goBenchStatusLine.FindStringSubmatch(line)
+			status := FAILED
+			if m[1] == "SKIP" {
+				status = SKIPPED
+			}
+
+			benchmarks = append(benchmarks, model.ScenarioResult{Name: m[2], Class: pkg, Status: status})
+
+		case goBenchPkgLine.MatchString(line):
+			m := goBenchPkgLine.FindStringSubmatch(line)
+			elapsed, _ := strconv.ParseFloat(m[3], 64)
+			suiteResult.TimeTaken += elapsed
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(benchmarks) == 0 {
+		return fmt.Errorf(errUnableToReadGoBench)
+	}
+
+	for _, benchmark := range benchmarks {
+		AddScenarioResult(suiteResult, benchmark)
+	}
+
+	return nil
+}
+
+// goBenchMetrics reads value and unit pairs of a benchmark result, values which are not numbers are skipped
+func goBenchMetrics(s string) []model.BenchmarkMetric {
+	fields := strings.Fields(s)
+	metrics := make([]model.BenchmarkMetric, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			continue
+		}
+
+		unit := fields[i+1]
+		name := goBenchMetricNames[unit]
+		if name == "" {
+			name = unit
+		}
+
+		metrics = append(metrics, model.BenchmarkMetric{Name: name, Value: value, Unit: unit})
+	}
+
+	return metrics
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidGoBenchContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "gobench",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToReadGoBench), err)
+}
+
+func TestGoBenchReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "gobench",
+	}
+
+	contents := `goos: linux
+goarch: amd64
+pkg: treco/report
+cpu: Intel(R) Xeon(R) CPU @ 2.20GHz
+BenchmarkParse-8            	   20000	     50000 ns/op	    2048 B/op	      12 allocs/op
+BenchmarkDecompress/size=1k-8         	  100000	     12000 ns/op	  85.33 MB/s
+BenchmarkDecompress/size=1m-8         	     100	  11000000 ns/op	  95.32 MB/s	   3.000 frames/op
+--- SKIP: BenchmarkArchive
+    archive_test.go:12: no archive
+--- FAIL: BenchmarkNormalize-8
+    normalize_test.go:20: unexpected name
+FAIL
+exit status 1
+FAIL	treco/report	3.512s
+`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(5), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(3), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 3.512, data.SuiteResult.TimeTaken, 0.0001)
+
+	parse := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "BenchmarkParse", parse.Name)
+	require.Equal(t, "treco/report", parse.Class)
+	require.Equal(t, PASSED, parse.Status)
+	require.InDelta(t, 1, parse.TimeTaken, 0.0001)
+	require.Equal(t, []model.BenchmarkMetric{
+		{Name: "time", Value: 50000, Unit: "ns/op"},
+		{Name: "memory", Value: 2048, Unit: "B/op"},
+		{Name: "allocations", Value: 12, Unit: "allocs/op"},
+	}, parse.Metrics)
+
+	// Sub benchmarks are normalized to their scenario
+	decompress := data.SuiteResult.ScenarioResults[2]
+	require.Equal(t, "BenchmarkDecompress", decompress.Name)
+	require.Equal(t, "size=1m", decompress.Parameters)
+	require.Equal(t, []model.BenchmarkMetric{
+		{Name: "time", Value: 11000000, Unit: "ns/op"},
+		{Name: "throughput", Value: 95.32, Unit: "MB/s"},
+		{Name: "frames/op", Value: 3, Unit: "frames/op"},
+	}, decompress.Metrics)
+
+	normalize := data.SuiteResult.ScenarioResults[4]
+	require.Equal(t, "BenchmarkNormalize", normalize.Name)
+	require.Equal(t, FAILED, normalize.Status)
+	require.Empty(t, normalize.Metrics)
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var errInvalidCoverProfileLine = "invalid coverprofile line: %v"
+
+type goCoverProfileParser struct{}
+
+// parse reads blocks of go coverprofile, coverage is of statements as go does not report lines or branches.
+// Profiles merged from several runs list the same block more than once
+func (goCoverProfileParser) parse(r io.Reader, result *coverage) error {
+	log.Println("reading go coverprofile")
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// <file>:<start line>.<start column>,<end line>.<end column> <statements> <count>
+		fields := strings.Fields(line)
+		i := strings.LastIndex(line, ":")
+		if len(fields) != 3 || i < 0 {
+			return fmt.Errorf(errInvalidCoverProfileLine, line)
+		}
+
+		block := strings.Fields(line[i+1:])[0]
+		statements, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf(errInvalidCoverProfileLine, line)
+		}
+
+		count, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf(errInvalidCoverProfileLine, line)
+		}
+
+		file := line[:i]
+		result.addLine(path.Dir(file), path.Base(file), block, uint(statements), count, 0, 0)
+	}
+
+	return scanner.Err()
+}
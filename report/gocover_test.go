@@ -0,0 +1,47 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidGoCoverProfileContent(t *testing.T) {
+	contents := "mode: set\ntreco/report/parser.go:37.51,39.16 two 1\n"
+	err := ParseCoverage(&model.Data{}, bytes.NewReader([]byte(contents)))
+	require.Equal(t, fmt.Errorf(errInvalidCoverProfileLine, "treco/report/parser.go:37.51,39.16 two 1"), err)
+}
+
+func TestGoCoverProfileParsing(t *testing.T) {
+	data := &model.Data{}
+
+	contents := `mode: atomic
+treco/report/parser.go:37.51,39.16 2 5
+treco/report/parser.go:39.16,41.3 1 0
+treco/report/parser.go:43.2,43.21 1 5
+treco/report/detect.go:30.40,33.2 3 0
+treco/model/data.go:120.50,122.2 4 1
+treco/report/parser.go:39.16,41.3 1 2
+`
+
+	err := ParseCoverage(data, bytes.NewReader([]byte(contents)))
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, 72.73, data.SuiteResult.Coverage)
+	require.Equal(t, 0.0, data.SuiteResult.BranchCoverage)
+	require.Equal(t, 2, len(data.SuiteResult.Packages))
+
+	pkg := data.SuiteResult.Packages[0]
+	require.Equal(t, "treco/report", pkg.Name)
+	require.Equal(t, uint(4), pkg.LinesCovered)
+	require.Equal(t, uint(7), pkg.LinesValid)
+	require.Equal(t, "parser.go", pkg.Files[0].Name)
+	require.Equal(t, 100.0, pkg.Files[0].LineCoverage)
+	require.Equal(t, "detect.go", pkg.Files[1].Name)
+	require.Equal(t, 0.0, pkg.Files[1].LineCoverage)
+
+	require.Equal(t, "treco/model", data.SuiteResult.Packages[1].Name)
+	require.Equal(t, 100.0, data.SuiteResult.Packages[1].LineCoverage)
+}
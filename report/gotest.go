@@ -0,0 +1,141 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"treco/model"
+)
+
+// GoTestEvent struct, a single line of `go test -json` output
+type GoTestEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+}
+
+var (
+	errUnableToUnmarshalToGoTest = "unmarshalling to go test events failed"
+)
+
+type goTestJSONParser struct{}
+
+// goTest is a test or subtest of a package
+type goTest struct {
+	pkg     string
+	name    string
+	status  string
+	elapsed float64
+}
+
+func init() {
+	Register("gotest", goTestJSONParser{})
+}
+
+func (goTestJSONParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	tests := make(map[string]*goTest)
+	order := make([]*goTest, 0)
+	events := 0
+
+	log.Println("reading go test events")
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		// Build output and other non json lines can be interleaved with the events
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && line[0] == '{' {
+			event := GoTestEvent{}
+			if jsonErr := json.Unmarshal(line, &event); jsonErr != nil {
+				return fmt.Errorf(errUnableToUnmarshalToGoTest)
+			}
+
+			// Other json printed by tests or tools is not a test2json event
+			if event.Action == "" {
+				continue
+			}
+
+			events++
+
+			if event.Test == "" {
+				// Package level result
+				if event.Action == "pass" || event.Action == "fail" {
+					suiteResult.TimeTaken += event.Elapsed
+				}
+			} else {
+				key := event.Package + "/" + event.Test
+				test, ok := tests[key]
+				if !ok {
+					test = &goTest{pkg: event.Package, name: event.Test}
+					tests[key] = test
+					order = append(order, test)
+				}
+
+				switch event.Action {
+				case "pass":
+					test.status = PASSED
+					test.elapsed = event.Elapsed
+				case "fail":
+					test.status = FAILED
+					test.elapsed = event.Elapsed
+				case "skip":
+					test.status = SKIPPED
+					test.elapsed = event.Elapsed
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if events == 0 {
+		return fmt.Errorf(errUnableToUnmarshalToGoTest)
+	}
+
+	// Parent tests pass or fail along with their subtests, hence only leaf tests are counted
+	parents := make(map[string]bool)
+	failedSubtests := make(map[string]bool)
+	for _, test := range order {
+		for parent := path.Dir(test.name); parent != "."; parent = path.Dir(parent) {
+			parents[test.pkg+"/"+parent] = true
+			if test.status == "" || test.status == FAILED {
+				failedSubtests[test.pkg+"/"+parent] = true
+			}
+		}
+	}
+
+	for _, test := range order {
+		key := test.pkg + "/" + test.name
+
+		// Tests which never reported a result were interrupted by a panic or timeout
+		if test.status == "" {
+			test.status = FAILED
+		}
+
+		// A parent which failed on its own is kept, as none of its subtests show the failure
+		if parents[key] && (test.status != FAILED || failedSubtests[key]) {
+			continue
+		}
+
+		AddScenarioResult(suiteResult, model.ScenarioResult{
+			Name:      test.name,
+			Class:     test.pkg,
+			Status:    test.status,
+			TimeTaken: test.elapsed,
+		})
+	}
+
+	return nil
+}
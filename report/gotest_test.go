@@ -0,0 +1,83 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidGoTestContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "gotest",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToGoTest), err)
+}
+
+func TestGoTestReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "gotest",
+	}
+
+	contents := `{"Action":"start","Package":"treco/report"}
+{"Action":"run","Package":"treco/report","Test":"TestParse"}
+{"Action":"output","Package":"treco/report","Test":"TestParse","Output":"=== RUN   TestParse\n"}
+{"Action":"run","Package":"treco/report","Test":"TestParse/case_1"}
+{"Action":"pass","Package":"treco/report","Test":"TestParse/case_1","Elapsed":0.25}
+{"Action":"pass","Package":"treco/report","Test":"TestParse","Elapsed":0.5}
+{"Action":"run","Package":"treco/report","Test":"TestSkip"}
+{"Action":"skip","Package":"treco/report","Test":"TestSkip","Elapsed":0}
+{"Action":"run","Package":"treco/report","Test":"TestFail"}
+{"Action":"fail","Package":"treco/report","Test":"TestFail","Elapsed":1.5}
+{"Action":"run","Package":"treco/report","Test":"TestCleanup"}
+{"Action":"run","Package":"treco/report","Test":"TestCleanup/case_1"}
+{"Action":"pass","Package":"treco/report","Test":"TestCleanup/case_1","Elapsed":0.1}
+{"Action":"fail","Package":"treco/report","Test":"TestCleanup","Elapsed":0.2}
+{"level":"info","msg":"json logged by a test"}
+{"Action":"fail","Package":"treco/report","Elapsed":2.1}
+# treco/storage
+storage/storage.go:1:1: some build error
+{"Action":"run","Package":"treco/storage","Test":"TestPanics"}
+{"Action":"fail","Package":"treco/storage","Elapsed":0.4}
+`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	// Parents are not counted, unless they failed while their subtests passed
+	require.Equal(t, uint(6), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(3), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 2.5, data.SuiteResult.TimeTaken, 0.0001)
+	require.Equal(t, 6, len(data.SuiteResult.ScenarioResults))
+
+	parse := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "TestParse", parse.Name)
+	require.Equal(t, "case_1", parse.Parameters)
+	require.Equal(t, "treco/report", parse.Class)
+	require.Equal(t, PASSED, parse.Status)
+	require.InDelta(t, 0.25, parse.TimeTaken, 0.0001)
+
+	cleanup := data.SuiteResult.ScenarioResults[3]
+	require.Equal(t, "TestCleanup", cleanup.Name)
+	require.Equal(t, "", cleanup.Parameters)
+	require.Equal(t, FAILED, cleanup.Status)
+
+	panics := data.SuiteResult.ScenarioResults[5]
+	require.Equal(t, "treco/storage", panics.Class)
+	require.Equal(t, FAILED, panics.Status)
+}
+
+func TestGoTestReportWithoutEvents(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "gotest",
+	}
+
+	contents := `{"level":"info","msg":"not an event"}`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToGoTest), err)
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+)
+
+// JacocoSourceFile struct
+type JacocoSourceFile struct {
+	Name  string       `xml:"name,attr"`
+	Lines []JacocoLine `xml:"line"`
+}
+
+// JacocoLine struct, with missed and covered instructions and branches
+type JacocoLine struct {
+	Number              string `xml:"nr,attr"`
+	MissedInstructions  uint   `xml:"mi,attr"`
+	CoveredInstructions uint   `xml:"ci,attr"`
+	MissedBranches      uint   `xml:"mb,attr"`
+	CoveredBranches     uint   `xml:"cb,attr"`
+}
+
+var errUnableToUnmarshalToJacoco = "unmarshalling to jacoco failed"
+
+type jacocoXMLParser struct{}
+
+// parse decodes source files one at a time, packages can be nested in groups for multi module reports
+func (jacocoXMLParser) parse(r io.Reader, result *coverage) error {
+	log.Println("decoding jacoco coverage report")
+	decoder := xml.NewDecoder(r)
+	pkg := ""
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf(errUnableToUnmarshalToJacoco)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "package":
+			pkg = xmlAttr(start, "name")
+
+		case "sourcefile":
+			sourceFile := JacocoSourceFile{}
+			if err := decoder.DecodeElement(&sourceFile, &start); err != nil {
+				return fmt.Errorf(errUnableToUnmarshalToJacoco)
+			}
+
+			for _, line := range sourceFile.Lines {
+				hits := uint64(0)
+				if line.CoveredInstructions > 0 {
+					hits = 1
+				}
+
+				result.addLine(pkg, sourceFile.Name, line.Number, 1, hits, line.CoveredBranches,
+					line.MissedBranches+line.CoveredBranches)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidJacocoContent(t *testing.T) {
+	contents := `<report name="app"><package name="com/example"><sourcefile name="A.java"><line nr="1"`
+	err := ParseCoverage(&model.Data{}, bytes.NewReader([]byte(contents)))
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToJacoco), err)
+}
+
+func TestJacocoReportParsing(t *testing.T) {
+	data := &model.Data{}
+
+	contents := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="app">
+	<sessioninfo id="host-1" start="1700000000000" dump="1700000001000"/>
+	<group name="core">
+		<package name="com/example/core">
+			<class name="com/example/core/Calculator" sourcefilename="Calculator.java">
+				<method name="add" desc="(II)I" line="3">
+					<counter type="LINE" missed="0" covered="1"/>
+				</method>
+			</class>
+			<sourcefile name="Calculator.java">
+				<line nr="3" mi="0" ci="4" mb="0" cb="0"/>
+				<line nr="5" mi="0" ci="2" mb="1" cb="3"/>
+				<line nr="6" mi="3" ci="0" mb="0" cb="0"/>
+				<counter type="LINE" missed="1" covered="2"/>
+				<counter type="BRANCH" missed="1" covered="3"/>
+			</sourcefile>
+		</package>
+	</group>
+	<package name="com/example/api">
+		<sourcefile name="Handler.java">
+			<line nr="10" mi="0" ci="5" mb="0" cb="0"/>
+		</sourcefile>
+	</package>
+	<counter type="LINE" missed="1" covered="3"/>
+</report>`
+
+	err := ParseCoverage(data, bytes.NewReader([]byte(contents)))
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, 75.0, data.SuiteResult.Coverage)
+	require.Equal(t, 75.0, data.SuiteResult.BranchCoverage)
+	require.Equal(t, 2, len(data.SuiteResult.Packages))
+
+	pkg := data.SuiteResult.Packages[0]
+	require.Equal(t, "com/example/core", pkg.Name)
+	require.Equal(t, uint(2), pkg.LinesCovered)
+	require.Equal(t, uint(3), pkg.LinesValid)
+	require.Equal(t, uint(3), pkg.BranchesCovered)
+	require.Equal(t, uint(4), pkg.BranchesValid)
+	require.Equal(t, 66.67, pkg.LineCoverage)
+	require.Equal(t, "Calculator.java", pkg.Files[0].Name)
+
+	require.Equal(t, "com/example/api", data.SuiteResult.Packages[1].Name)
+	require.Equal(t, 100.0, data.SuiteResult.Packages[1].LineCoverage)
+}
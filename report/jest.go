@@ -0,0 +1,164 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"treco/model"
+)
+
+// JestReport struct, output of jest --json
+type JestReport struct {
+	TestResults []JestTestResult `json:"testResults"`
+}
+
+// JestTestResult struct with results of a test file
+type JestTestResult struct {
+	Name             string                `json:"name"`
+	Status           string                `json:"status"`
+	Message          string                `json:"message"`
+	AssertionResults []JestAssertionResult `json:"assertionResults"`
+}
+
+// JestAssertionResult struct with result of a test, duration is in milliseconds
+type JestAssertionResult struct {
+	AncestorTitles  []string `json:"ancestorTitles"`
+	Title           string   `json:"title"`
+	Status          string   `json:"status"`
+	Duration        float64  `json:"duration"`
+	FailureMessages []string `json:"failureMessages"`
+	Invocations     uint     `json:"invocations"`
+}
+
+var (
+	errUnableToUnmarshalToJest = "unmarshalling to jest failed"
+)
+
+type jestJSONParser struct{}
+
+func init() {
+	Register("jest", jestJSONParser{})
+}
+
+func (jestJSONParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+	limit := outputSizeLimit()
+
+	report := JestReport{}
+
+	log.Println("unmarshalling to jest report")
+	if err := json.NewDecoder(r).Decode(&report); err != nil || report.TestResults == nil {
+		return fmt.Errorf(errUnableToUnmarshalToJest)
+	}
+
+	// Test files are named relative to the directory holding all of them, so that files of the same name in
+	// different directories stay apart
+	root := jestRoot(report.TestResults)
+
+	for _, testResult := range report.TestResults {
+		file := jestFileName(testResult.Name, root)
+
+		// Test file which failed to run, e.g. on a syntax error, has no assertion results
+		if len(testResult.AssertionResults) == 0 && testResult.Status == "failed" {
+			AddScenarioResult(suiteResult, model.ScenarioResult{
+				Name:      file,
+				Class:     file,
+				Status:    FAILED,
+				Message:   truncate(firstLine(testResult.Message), limit),
+				SuiteName: testResult.Name,
+			})
+
+			continue
+		}
+
+		for _, ar := range testResult.AssertionResults {
+			timeTaken := ar.Duration / 1000
+			suiteResult.TimeTaken += timeTaken
+
+			status := PASSED
+			switch ar.Status {
+			case "failed":
+				status = FAILED
+			case "pending", "skipped", "todo", "disabled":
+				status = SKIPPED
+			}
+
+			// Describe blocks of the same name in different files are kept apart by the file name
+			class := strings.Join(append([]string{file}, ar.AncestorTitles...), junitSuitePathSeparator)
+
+			// Invocations are only reported when tests are retried
+			attempts := ar.Invocations
+			if attempts == 0 {
+				attempts = 1
+			}
+
+			if status == PASSED && attempts > 1 {
+				status = FLAKY
+			}
+
+			failure := strings.Join(ar.FailureMessages, "\n")
+			AddScenarioResult(suiteResult, model.ScenarioResult{
+				Name:       ar.Title,
+				Class:      class,
+				Status:     status,
+				TimeTaken:  timeTaken,
+				Attempts:   attempts,
+				Message:    truncate(firstLine(failure), limit),
+				StackTrace: truncate(failure, limit),
+				SuiteName:  testResult.Name,
+				SuitePath:  class,
+			})
+		}
+	}
+
+	return nil
+}
+
+// firstLine of s, messages of javascript errors are followed by their stack
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// jestRoot returns the deepest directory holding every test file
+func jestRoot(testResults []JestTestResult) string {
+	root := ""
+	for i, testResult := range testResults {
+		dir := path.Dir(jestSlashes(testResult.Name))
+		if i == 0 {
+			root = dir
+			continue
+		}
+
+		for root != "." && root != "/" && dir != root && !strings.HasPrefix(dir, root+"/") {
+			root = path.Dir(root)
+		}
+	}
+
+	return root
+}
+
+// jestFileName returns name of the test file relative to root
+func jestFileName(name, root string) string {
+	name = jestSlashes(name)
+	switch root {
+	case ".":
+		return name
+	case "/":
+		return strings.TrimPrefix(name, "/")
+	}
+
+	return strings.TrimPrefix(name, root+"/")
+}
+
+// jestSlashes replaces the separators of Windows paths
+func jestSlashes(name string) string {
+	return strings.ReplaceAll(name, `\`, "/")
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidJestContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "jest",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToJest), err)
+}
+
+func TestJestReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "jest",
+	}
+
+	contents := `{
+	"numFailedTests": 1,
+	"numTotalTests": 4,
+	"success": false,
+	"testResults": [
+		{
+			"name": "/builds/app/src/calculator.test.js",
+			"status": "failed",
+			"message": "",
+			"assertionResults": [
+				{"ancestorTitles": ["Calculator", "add"], "title": "adds numbers", "status": "passed", "duration": 12, "failureMessages": []},
+				{"ancestorTitles": ["Calculator", "add"], "title": "adds negative numbers", "status": "failed", "duration": 8,
+					"failureMessages": ["Error: expect(received).toBe(expected)\n\nExpected: -2\nReceived: 2\n    at Object.<anonymous> (calculator.test.js:10:5)"]},
+				{"ancestorTitles": ["Calculator"], "title": "divides", "status": "pending", "duration": null, "failureMessages": []},
+				{"ancestorTitles": [], "title": "retried", "status": "passed", "duration": 980, "failureMessages": [], "invocations": 3}
+			]
+		},
+		{
+			"name": "/builds/app/src/broken.test.js",
+			"status": "failed",
+			"message": "Test suite failed to run\n\nSyntaxError: Unexpected token",
+			"assertionResults": []
+		}
+	]
+}`
+
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+
+	suiteResult := data.SuiteResult
+	require.Equal(t, uint(5), suiteResult.TotalExecuted)
+	require.Equal(t, uint(1), suiteResult.TotalPassed)
+	require.Equal(t, uint(1), suiteResult.TotalFlaky)
+	require.Equal(t, uint(2), suiteResult.TotalFailed)
+	require.Equal(t, uint(1), suiteResult.TotalSkipped)
+	require.Equal(t, 1.0, suiteResult.TimeTaken)
+
+	scenarioResults := suiteResult.ScenarioResults
+	require.Equal(t, "adds numbers", scenarioResults[0].Name)
+	require.Equal(t, "calculator.test.js > Calculator > add", scenarioResults[0].Class)
+	require.Equal(t, "/builds/app/src/calculator.test.js", scenarioResults[0].SuiteName)
+	require.Equal(t, uint(1), scenarioResults[0].Attempts)
+
+	require.Equal(t, FAILED, scenarioResults[1].Status)
+	require.Equal(t, "Error: expect(received).toBe(expected)", scenarioResults[1].Message)
+	require.Contains(t, scenarioResults[1].StackTrace, "calculator.test.js:10:5")
+
+	require.Equal(t, SKIPPED, scenarioResults[2].Status)
+
+	require.Equal(t, "calculator.test.js", scenarioResults[3].Class)
+	require.Equal(t, uint(3), scenarioResults[3].Attempts)
+	require.Equal(t, FLAKY, scenarioResults[3].Status)
+
+	require.Equal(t, "broken.test.js", scenarioResults[4].Name)
+	require.Equal(t, FAILED, scenarioResults[4].Status)
+	require.Equal(t, "Test suite failed to run", scenarioResults[4].Message)
+}
+
+// nolint: scopelint
+func TestJestTestFilesOfTheSameName(t *testing.T) {
+	reports := map[string][]string{
+		"unix":    {"/builds/app/src/a/index.test.js", "/builds/app/src/b/index.test.js"},
+		"windows": {`C:\\builds\\app\\src\\a\\index.test.js`, `C:\\builds\\app\\src\\b\\index.test.js`},
+	}
+
+	for name, files := range reports {
+		t.Run(name, func(t *testing.T) {
+			data := &model.Data{
+				ReportFormat: "jest",
+			}
+
+			contents := fmt.Sprintf(`{"testResults": [
+				{"name": "%v", "status": "passed",
+					"assertionResults": [{"ancestorTitles": ["index"], "title": "renders", "status": "passed", "duration": 1}]},
+				{"name": "%v", "status": "failed", "assertionResults": []}
+			]}`, files[0], files[1])
+
+			err := Parse(bytes.NewReader([]byte(contents)), data)
+			require.NoError(t, err, "Parsing error")
+
+			results := data.SuiteResult.ScenarioResults
+			require.Equal(t, "a/index.test.js > index", results[0].Class)
+			require.Equal(t, "b/index.test.js", results[1].Class)
+		})
+	}
+}
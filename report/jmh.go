@@ -0,0 +1,131 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"treco/model"
+)
+
+// JmhBenchmark struct, a benchmark of a JMH json result
+type JmhBenchmark struct {
+	Benchmark        string               `json:"benchmark"`
+	Mode             string               `json:"mode"`
+	Threads          int                  `json:"threads"`
+	Forks            int                  `json:"forks"`
+	Params           map[string]string    `json:"params"`
+	PrimaryMetric    JmhMetric            `json:"primaryMetric"`
+	SecondaryMetrics map[string]JmhMetric `json:"secondaryMetrics"`
+}
+
+// JmhMetric struct
+type JmhMetric struct {
+	Score      float64         `json:"score"`
+	ScoreError json.RawMessage `json:"scoreError"`
+	ScoreUnit  string          `json:"scoreUnit"`
+}
+
+var (
+	errUnableToUnmarshalToJmh = "unmarshalling to jmh result failed"
+
+	// Names of the primary metric for each benchmark mode
+	jmhModeMetricNames = map[string]string{
+		"thrpt":  "throughput",
+		"avgt":   "time",
+		"sample": "time",
+		"ss":     "time",
+	}
+)
+
+type jmhJSONParser struct{}
+
+func init() {
+	Register("jmh", jmhJSONParser{})
+}
+
+func (jmhJSONParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+	benchmarks := make([]JmhBenchmark, 0)
+
+	log.Println("unmarshalling to jmh result")
+	if err := json.NewDecoder(r).Decode(&benchmarks); err != nil || len(benchmarks) == 0 {
+		return fmt.Errorf(errUnableToUnmarshalToJmh)
+	}
+
+	for _, b := range benchmarks {
+		if b.Benchmark == "" {
+			return fmt.Errorf(errUnableToUnmarshalToJmh)
+		}
+
+		// Benchmark is the fully qualified name of the benchmark method
+		class, name := "", b.Benchmark
+		if i := strings.LastIndex(b.Benchmark, "."); i >= 0 {
+			class, name = b.Benchmark[:i], b.Benchmark[i+1:]
+		}
+
+		params := jmhParams(b.Params)
+		if params != "" {
+			name = fmt.Sprintf("%s[%s]", name, params)
+		}
+
+		metrics := []model.BenchmarkMetric{jmhMetric(jmhModeMetricNames[b.Mode], b.Mode, b.PrimaryMetric)}
+
+		secondary := make([]string, 0, len(b.SecondaryMetrics))
+		for key := range b.SecondaryMetrics {
+			secondary = append(secondary, key)
+		}
+
+		sort.Strings(secondary)
+		for _, key := range secondary {
+			// Profiler metrics are prefixed with a middle dot, e.g. ·gc.alloc.rate.norm
+			metrics = append(metrics, jmhMetric(strings.TrimPrefix(key, "·"), key, b.SecondaryMetrics[key]))
+		}
+
+		AddScenarioResult(suiteResult, model.ScenarioResult{
+			Name:       name,
+			Class:      class,
+			Status:     PASSED,
+			Parameters: params,
+			Metrics:    metrics,
+		})
+	}
+
+	return nil
+}
+
+// jmhMetric converts a metric, name falls back to the given one when empty
+func jmhMetric(name, fallback string, m JmhMetric) model.BenchmarkMetric {
+	if name == "" {
+		name = fallback
+	}
+
+	// Error is "NaN" when there are too few iterations to compute it
+	scoreError, err := strconv.ParseFloat(strings.Trim(string(m.ScoreError), `"`), 64)
+	if err != nil || math.IsNaN(scoreError) {
+		scoreError = 0
+	}
+
+	return model.BenchmarkMetric{Name: name, Value: m.Score, Error: scoreError, Unit: m.ScoreUnit}
+}
+
+// jmhParams joins params sorted by name as name=value
+func jmhParams(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, key+"="+params[key])
+	}
+
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidJmhContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "jmh",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToJmh), err)
+
+	// Arrays without jmh results look like cucumber reports
+	for _, contents := range []string{`[]`, `[{"mode": "thrpt"}]`} {
+		err := Parse(bytes.NewReader([]byte(contents)), data)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errUnableToUnmarshalToJmh)
+	}
+}
+
+func TestJmhReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "jmh",
+	}
+
+	contents := `[
+    {
+        "jmhVersion" : "1.36",
+        "benchmark" : "org.sample.ParserBenchmark.parse",
+        "mode" : "thrpt",
+        "threads" : 1,
+        "forks" : 1,
+        "params" : {
+            "size" : "1000",
+            "format" : "json"
+        },
+        "primaryMetric" : {
+            "score" : 1523.4,
+            "scoreError" : 12.5,
+            "scoreConfidence" : [1510.9, 1535.9],
+            "scoreUnit" : "ops/s",
+            "rawData" : [[1520.1, 1526.7]]
+        },
+        "secondaryMetrics" : {
+            "·gc.alloc.rate.norm" : {
+                "score" : 4096.0,
+                "scoreError" : "NaN",
+                "scoreUnit" : "B/op"
+            }
+        }
+    },
+    {
+        "jmhVersion" : "1.36",
+        "benchmark" : "org.sample.ParserBenchmark.detect",
+        "mode" : "avgt",
+        "primaryMetric" : {
+            "score" : 250.5,
+            "scoreError" : "NaN",
+            "scoreUnit" : "ns/op"
+        },
+        "secondaryMetrics" : {}
+    }
+]`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(2), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+
+	parse := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "parse", parse.Name)
+	require.Equal(t, "org.sample.ParserBenchmark", parse.Class)
+	require.Equal(t, "format=json, size=1000", parse.Parameters)
+	require.Equal(t, []model.BenchmarkMetric{
+		{Name: "throughput", Value: 1523.4, Error: 12.5, Unit: "ops/s"},
+		{Name: "gc.alloc.rate.norm", Value: 4096, Unit: "B/op"},
+	}, parse.Metrics)
+
+	detect := data.SuiteResult.ScenarioResults[1]
+	require.Equal(t, "detect", detect.Name)
+	require.Equal(t, "", detect.Parameters)
+	require.Equal(t, []model.BenchmarkMetric{{Name: "time", Value: 250.5, Unit: "ns/op"}}, detect.Metrics)
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
+	"strconv"
 	"strings"
+	"time"
+	"treco/conf"
 	"treco/model"
+	"unicode"
 )
 
 // Expected Execution statuses
@@ -15,92 +18,464 @@ const (
 	PASSED  = "passed"
 	FAILED  = "failed"
 	SKIPPED = "skipped"
+	// FLAKY is a test which passed only after being rerun
+	FLAKY = "flaky"
 )
 
-// JunitReport struct
-type JunitReport struct {
-	XMLName    xml.Name         `xml:"testsuites"`
-	TestSuites []JunitTestSuite `xml:"testsuite"`
-}
-
-// JunitTestSuite struct
+// JunitTestSuite struct, test cases and nested suites are not part of it as they are decoded one at a time
 type JunitTestSuite struct {
-	XMLName        xml.Name        `xml:"testsuite"`
-	Tests          uint            `xml:"tests,attr"`
-	Skipped        uint            `xml:"skipped,attr"`
-	Failures       uint            `xml:"failures,attr"`
-	Errors         uint            `xml:"errors,attr"`
-	Time           float64         `xml:"time,attr"`
-	JunitTestCases []JunitTestCase `xml:"testcase"`
+	XMLName   xml.Name `xml:"testsuite"`
+	Name      string   `xml:"name,attr"`
+	Hostname  string   `xml:"hostname,attr"`
+	Timestamp string   `xml:"timestamp,attr"`
+	Tests     uint     `xml:"tests,attr"`
+	Skipped   uint     `xml:"skipped,attr"`
+	Failures  uint     `xml:"failures,attr"`
+	Errors    uint     `xml:"errors,attr"`
+	Time      float64  `xml:"time,attr"`
 }
 
 // JunitTestCase struct
 type JunitTestCase struct {
-	XMLName  xml.Name  `xml:"testcase"`
-	Name     string    `xml:"name,attr"`
-	Class    string    `xml:"classname,attr"`
-	Time     float64   `xml:"time,attr"`
-	Features string    `xml:"features,attr"`
-	Failure  *struct{} `xml:"failure,omitempty"`
-	Skipped  *struct{} `xml:"skipped,omitempty"`
-	Error    *struct{} `xml:"error,omitempty"`
+	XMLName    xml.Name        `xml:"testcase"`
+	Name       string          `xml:"name,attr"`
+	Class      string          `xml:"classname,attr"`
+	Time       float64         `xml:"time,attr"`
+	Features   string          `xml:"features,attr"`
+	Failure    *JunitMessage   `xml:"failure,omitempty"`
+	Skipped    *JunitMessage   `xml:"skipped,omitempty"`
+	Error      *JunitMessage   `xml:"error,omitempty"`
+	SystemOut  string          `xml:"system-out"`
+	SystemErr  string          `xml:"system-err"`
+	Properties []JunitProperty `xml:"properties>property"`
+	// Reruns of the test written by surefire and gradle, flaky ones are followed by a pass
+	FlakyFailures []JunitRerun `xml:"flakyFailure"`
+	FlakyErrors   []JunitRerun `xml:"flakyError"`
+	RerunFailures []JunitRerun `xml:"rerunFailure"`
+	RerunErrors   []JunitRerun `xml:"rerunError"`
+}
+
+// JunitProperties struct
+type JunitProperties struct {
+	Properties []JunitProperty `xml:"property"`
+}
+
+// JunitProperty struct, value can either be an attribute or the element text
+type JunitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Body  string `xml:",chardata"`
+}
+
+// JunitMessage struct for failure, error and skipped elements
+type JunitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
 }
 
+// JunitRerun struct for a failed run of a rerun test
+type JunitRerun struct {
+	Message    string `xml:"message,attr"`
+	Type       string `xml:"type,attr"`
+	StackTrace string `xml:"stackTrace"`
+	SystemOut  string `xml:"system-out"`
+	SystemErr  string `xml:"system-err"`
+}
+
+// FeatureProperty is the environment variable with comma separated names of test properties, e.g. requirement or jira,
+// whose values are captured as features
+const FeatureProperty = "FEATURE_PROPERTY"
+
+// Separator between names of nested suites in suite path
+const junitSuitePathSeparator = " > "
+
 var (
 	errUnableToUnmarshalToJunit = "unmarshalling to junit failed"
+
+	// Layouts seen in timestamp attribute, most producers omit the time zone
+	junitTimestampLayouts = [...]string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04:05 MST",
+		"2006-01-02 15:04:05"}
 )
 
 type junitXMLParser struct{}
 
-func (junitXMLParser) parse(r io.Reader, result *model.Data) error {
-	suiteResult := &result.SuiteResult
+func init() {
+	Register("junit", junitXMLParser{})
+}
 
-	log.Println("reading report file")
-	b, err := ioutil.ReadAll(r)
-	if err != nil {
-		fmt.Println("error reading: " + err.Error())
-		return err
+// Parse decodes the report as a token stream, so that large reports are never held in memory as a whole
+func (junitXMLParser) Parse(r io.Reader, result *model.Data) error {
+	stream := &junitStream{
+		suiteResult:       &result.SuiteResult,
+		limit:             outputSizeLimit(),
+		featureProperties: featurePropertyNames(),
 	}
 
-	jur := JunitReport{}
+	log.Println("decoding junit report")
+	decoder := xml.NewDecoder(r)
+	foundRoot := false
 
-	log.Println("unmarshalling to junit report")
-	if strings.Contains(string(b), "testsuites") {
-		if err = xml.Unmarshal(b, &jur); err != nil {
-			return fmt.Errorf(errUnableToUnmarshalToJunit)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
 		}
-	} else {
-		suite := JunitTestSuite{}
-		if err = xml.Unmarshal(b, &suite); err != nil {
+
+		if err != nil {
 			return fmt.Errorf(errUnableToUnmarshalToJunit)
 		}
 
-		jur.TestSuites = append(jur.TestSuites, suite)
+		if end, ok := token.(xml.EndElement); ok && end.Name.Local == "testsuite" {
+			stream.endSuite()
+			continue
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if !foundRoot {
+			if start.Name.Local != "testsuites" && start.Name.Local != "testsuite" {
+				return fmt.Errorf(errUnableToUnmarshalToJunit)
+			}
+
+			foundRoot = true
+		}
+
+		switch start.Name.Local {
+		case "testsuite":
+			stream.startSuite(start)
+
+		case "properties":
+			// Test case properties are decoded along with the test case, hence these belong to a suite
+			properties := JunitProperties{}
+			if err := decoder.DecodeElement(&properties, &start); err != nil {
+				return fmt.Errorf(errUnableToUnmarshalToJunit)
+			}
+
+			stream.addSuiteProperties(properties.Properties)
+
+		case "testcase":
+			tc := JunitTestCase{}
+			if err := decoder.DecodeElement(&tc, &start); err != nil {
+				return fmt.Errorf(errUnableToUnmarshalToJunit)
+			}
+
+			stream.addTestCase(tc)
+		}
+	}
+
+	if !foundRoot {
+		return fmt.Errorf(errUnableToUnmarshalToJunit)
+	}
+
+	return nil
+}
+
+// junitStream holds state while decoding, suites can be nested in each other (e.g. Jest, pytest and Ant)
+type junitStream struct {
+	suiteResult       *model.SuiteResult
+	limit             int
+	featureProperties []string
+	suites            []*junitSuiteFrame
+}
+
+// junitSuiteFrame is a suite being decoded, along with totals of its nested suites and of its test cases
+type junitSuiteFrame struct {
+	suite     JunitTestSuite
+	path      string
+	hostname  string
+	timestamp *time.Time
+	features  []string
+	children  JunitTestSuite
+	testCases JunitTestSuite
+}
+
+// current returns the innermost suite being decoded
+func (s *junitStream) current() *junitSuiteFrame {
+	if len(s.suites) == 0 {
+		return nil
+	}
+
+	return s.suites[len(s.suites)-1]
+}
+
+// startSuite pushes the suite, hostname, timestamp and features are inherited from the parent suite
+func (s *junitStream) startSuite(start xml.StartElement) {
+	suite := newJunitTestSuite(start)
+	frame := &junitSuiteFrame{
+		suite: suite,
+		path:  suite.Name,
+	}
+
+	if parent := s.current(); parent != nil {
+		frame.path = parent.path + junitSuitePathSeparator + suite.Name
+		frame.hostname = parent.hostname
+		frame.timestamp = parent.timestamp
+		frame.features = parent.features[:len(parent.features):len(parent.features)]
 	}
 
-	for _, suite := range jur.TestSuites {
-		suiteResult.TotalExecuted += suite.Tests
-		suiteResult.TotalFailed += suite.Failures + suite.Errors
-		suiteResult.TotalSkipped += suite.Skipped
-		suiteResult.TotalPassed += suite.Tests - (suite.Failures + suite.Skipped + suite.Errors)
-		suiteResult.TimeTaken += suite.Time
+	if suite.Hostname != "" {
+		frame.hostname = suite.Hostname
+	}
+
+	if timestamp := parseJunitTimestamp(suite.Timestamp); timestamp != nil {
+		frame.timestamp = timestamp
+	}
+
+	s.suites = append(s.suites, frame)
+}
+
+// endSuite pops the suite and adds its totals to the parent suite, or reconciles them with its test cases for the
+// outermost suite. Totals of a suite without tests attribute are taken from its nested suites
+func (s *junitStream) endSuite() {
+	frame := s.current()
+	if frame == nil {
+		return
+	}
+
+	s.suites = s.suites[:len(s.suites)-1]
+
+	totals := frame.suite
+	if totals.Tests == 0 {
+		totals.Tests = frame.children.Tests
+		totals.Failures = frame.children.Failures
+		totals.Errors = frame.children.Errors
+		totals.Skipped = frame.children.Skipped
+	}
+
+	if totals.Time == 0 {
+		totals.Time = frame.children.Time
+	}
+
+	if parent := s.current(); parent != nil {
+		parent.children.Tests += totals.Tests
+		parent.children.Failures += totals.Failures
+		parent.children.Errors += totals.Errors
+		parent.children.Skipped += totals.Skipped
+		parent.children.Time += totals.Time
+		parent.testCases.Tests += frame.testCases.Tests
+		parent.testCases.Failures += frame.testCases.Failures
+		parent.testCases.Skipped += frame.testCases.Skipped
+		return
+	}
+
+	s.suiteResult.TimeTaken += totals.Time
+	s.reconcileTotals(frame.suite.Name, totals, frame.testCases)
+}
 
-		for _, tc := range suite.JunitTestCases {
-			status := PASSED
-			if tc.Failure != nil || tc.Error != nil {
-				status = FAILED
-			} else if tc.Skipped != nil {
-				status = SKIPPED
+// reconcileTotals checks totals of the outermost suite against its test cases, which are already counted in suite
+// result. Suite attributes are often missing or wrong, hence they are only used for reports without test cases
+func (s *junitStream) reconcileTotals(name string, totals, testCases JunitTestSuite) {
+	suiteResult := s.suiteResult
+	failed := totals.Failures + totals.Errors
+
+	if testCases.Tests == 0 {
+		passed := uint(0)
+		if totals.Tests > failed+totals.Skipped {
+			passed = totals.Tests - failed - totals.Skipped
+		} else if totals.Tests < failed+totals.Skipped {
+			log.Printf("suite %v has more failed and skipped tests than tests\n", name)
+			suiteResult.TotalsMismatch = true
+		}
+
+		suiteResult.TotalExecuted += totals.Tests
+		suiteResult.TotalFailed += failed
+		suiteResult.TotalSkipped += totals.Skipped
+		suiteResult.TotalPassed += passed
+		return
+	}
+
+	if totals.Tests == 0 {
+		return
+	}
+
+	if totals.Tests != testCases.Tests || failed != testCases.Failures || totals.Skipped != testCases.Skipped {
+		log.Printf("suite %v declares %v tests, %v failed and %v skipped, but has %v, %v and %v test cases\n", name,
+			totals.Tests, failed, totals.Skipped, testCases.Tests, testCases.Failures, testCases.Skipped)
+		suiteResult.TotalsMismatch = true
+	}
+}
+
+// addSuiteProperties stores suite properties, features from them apply to all test cases of the suite.
+// Surefire and Gradle repeat the same system properties for every suite, which are stored once
+func (s *junitStream) addSuiteProperties(properties []JunitProperty) {
+	for _, property := range properties {
+		p := model.SuiteResultProperty{
+			Name:  property.Name,
+			Value: truncate(property.value(), s.limit),
+		}
+
+		if !hasSuiteProperty(s.suiteResult.Properties, p) {
+			s.suiteResult.Properties = append(s.suiteResult.Properties, p)
+		}
+	}
+
+	if frame := s.current(); frame != nil {
+		frame.features = append(frame.features, propertyFeatures(properties, s.featureProperties)...)
+	}
+}
+
+// hasSuiteProperty checks if a property of the same name and value is already stored
+func hasSuiteProperty(properties []model.SuiteResultProperty, p model.SuiteResultProperty) bool {
+	for _, existing := range properties {
+		if existing.Name == p.Name && existing.Value == p.Value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *junitStream) addTestCase(tc JunitTestCase) {
+	limit := s.limit
+
+	status := PASSED
+	details := tc.Failure
+	flaky := append(tc.FlakyFailures, tc.FlakyErrors...)
+	if tc.Failure != nil || tc.Error != nil {
+		status = FAILED
+		if details == nil {
+			details = tc.Error
+		}
+	} else if tc.Skipped != nil {
+		status = SKIPPED
+		details = tc.Skipped
+	} else if len(flaky) > 0 {
+		// Failure of the first run is kept, as the test passed in the end
+		status = FLAKY
+		details = &JunitMessage{Message: flaky[0].Message, Type: flaky[0].Type, Body: flaky[0].StackTrace}
+	}
+
+	scenarioResult := model.ScenarioResult{
+		SuiteResultID: s.suiteResult.ID,
+		Name:          tc.Name,
+		Class:         tc.Class,
+		Status:        status,
+		TimeTaken:     tc.Time,
+		Attempts:      1 + uint(len(flaky)+len(tc.RerunFailures)+len(tc.RerunErrors)),
+		Features:      strings.Split(tc.Features, " "),
+		SystemOut:     truncate(tc.SystemOut, limit),
+		SystemErr:     truncate(tc.SystemErr, limit),
+	}
+
+	if frame := s.current(); frame != nil {
+		scenarioResult.SuiteName = frame.suite.Name
+		scenarioResult.SuitePath = frame.path
+		scenarioResult.Hostname = frame.hostname
+		scenarioResult.Timestamp = frame.timestamp
+		scenarioResult.Features = append(scenarioResult.Features, frame.features...)
+
+		frame.testCases.Tests++
+		switch status {
+		case FAILED:
+			frame.testCases.Failures++
+		case SKIPPED:
+			frame.testCases.Skipped++
+		}
+	}
+
+	scenarioResult.Features = append(scenarioResult.Features, propertyFeatures(tc.Properties, s.featureProperties)...)
+
+	for _, property := range tc.Properties {
+		scenarioResult.Properties = append(scenarioResult.Properties, model.ScenarioResultProperty{
+			Name:  property.Name,
+			Value: truncate(property.value(), limit),
+		})
+	}
+
+	if details != nil {
+		scenarioResult.Message = truncate(details.Message, limit)
+		scenarioResult.FailureType = details.Type
+		scenarioResult.StackTrace = truncate(details.Body, limit)
+	}
+
+	AddScenarioResult(s.suiteResult, scenarioResult)
+}
+
+// newJunitTestSuite reads suite attributes from the start element, invalid numbers are taken as 0
+func newJunitTestSuite(start xml.StartElement) JunitTestSuite {
+	suite := JunitTestSuite{XMLName: start.Name}
+
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "tests":
+			suite.Tests = parseUint(attr.Value)
+		case "skipped":
+			suite.Skipped = parseUint(attr.Value)
+		case "failures":
+			suite.Failures = parseUint(attr.Value)
+		case "errors":
+			suite.Errors = parseUint(attr.Value)
+		case "time":
+			suite.Time, _ = strconv.ParseFloat(strings.TrimSpace(attr.Value), 64)
+		case "name":
+			suite.Name = attr.Value
+		case "hostname":
+			suite.Hostname = attr.Value
+		case "timestamp":
+			suite.Timestamp = attr.Value
+		}
+	}
+
+	return suite
+}
+
+func parseUint(s string) uint {
+	v, _ := strconv.ParseUint(strings.TrimSpace(s), 10, 0)
+	return uint(v)
+}
+
+// value of the property, from value attribute or element text
+func (p JunitProperty) value() string {
+	if p.Value != "" {
+		return p.Value
+	}
+
+	return strings.TrimSpace(p.Body)
+}
+
+// featurePropertyNames returns the configured property names which hold features
+func featurePropertyNames() []string {
+	names := make([]string, 0)
+	for _, name := range strings.Split(conf.Get(FeatureProperty), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// propertyFeatures returns values of the feature properties, a value can hold multiple features separated
+// by comma or space
+func propertyFeatures(properties []JunitProperty, names []string) []string {
+	features := make([]string, 0)
+	for _, property := range properties {
+		for _, name := range names {
+			if strings.EqualFold(property.Name, name) {
+				features = append(features, strings.FieldsFunc(property.value(), func(r rune) bool {
+					return r == ',' || unicode.IsSpace(r)
+				})...)
 			}
+		}
+	}
+
+	return features
+}
+
+// parseJunitTimestamp returns nil when timestamp is missing or in an unknown layout
+func parseJunitTimestamp(timestamp string) *time.Time {
+	timestamp = strings.TrimSpace(timestamp)
+	if timestamp == "" {
+		return nil
+	}
 
-			suiteResult.ScenarioResults = append(suiteResult.ScenarioResults, model.ScenarioResult{
-				SuiteResultID: suiteResult.ID,
-				Name:          tc.Name,
-				Class:         tc.Class,
-				Status:        status,
-				TimeTaken:     tc.Time,
-				Features:      strings.Split(tc.Features, " "),
-			})
+	for _, layout := range junitTimestampLayouts {
+		if t, err := time.Parse(layout, timestamp); err == nil {
+			return &t
 		}
 	}
 
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var errInvalidLcovRecord = "invalid lcov record: %v"
+
+type lcovParser struct{}
+
+// parse reads lcov tracefile records, only line (DA) and branch (BRDA) records are read as totals are computed from them
+func (lcovParser) parse(r io.Reader, result *coverage) error {
+	log.Println("reading lcov coverage report")
+	scanner := bufio.NewScanner(r)
+	record := newLcovRecord("")
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		kind, value, _ := strings.Cut(line, ":")
+		fields := strings.Split(value, ",")
+
+		switch kind {
+		case "SF":
+			record = newLcovRecord(value)
+
+		case "DA":
+			// DA:<line>,<hits>[,<checksum>]
+			if len(fields) < 2 {
+				return fmt.Errorf(errInvalidLcovRecord, line)
+			}
+
+			hits, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf(errInvalidLcovRecord, line)
+			}
+
+			if _, ok := record.hits[fields[0]]; !ok {
+				record.lines = append(record.lines, fields[0])
+			}
+
+			record.hits[fields[0]] += hits
+
+		case "BRDA":
+			// BRDA:<line>,<block>,<branch>,<taken>, taken is - when the line was never executed
+			if len(fields) != 4 {
+				return fmt.Errorf(errInvalidLcovRecord, line)
+			}
+
+			record.branchesValid[fields[0]]++
+			if fields[3] != "-" && fields[3] != "0" {
+				record.branchesCovered[fields[0]]++
+			}
+
+		case "end_of_record":
+			record.addTo(result)
+			record = newLcovRecord("")
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// Last record may miss its end marker
+	record.addTo(result)
+	return nil
+}
+
+// lcovRecord holds the lines of a source file, branches of a line are listed separately from its hits
+type lcovRecord struct {
+	file            string
+	lines           []string
+	hits            map[string]uint64
+	branchesCovered map[string]uint
+	branchesValid   map[string]uint
+}
+
+func newLcovRecord(file string) *lcovRecord {
+	return &lcovRecord{
+		file:            file,
+		lines:           make([]string, 0),
+		hits:            make(map[string]uint64),
+		branchesCovered: make(map[string]uint),
+		branchesValid:   make(map[string]uint),
+	}
+}
+
+// addTo adds lines of the source file to coverage, files are grouped in packages by their directory
+func (l *lcovRecord) addTo(result *coverage) {
+	if l.file == "" {
+		return
+	}
+
+	for _, line := range l.lines {
+		result.addLine(path.Dir(l.file), path.Base(l.file), line, 1, l.hits[line], l.branchesCovered[line],
+			l.branchesValid[line])
+	}
+}
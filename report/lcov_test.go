@@ -0,0 +1,65 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidLcovContent(t *testing.T) {
+	contents := "SF:src/a.js\nDA:1\nend_of_record\n"
+	err := ParseCoverage(&model.Data{}, bytes.NewReader([]byte(contents)))
+	require.Equal(t, fmt.Errorf(errInvalidLcovRecord, "DA:1"), err)
+}
+
+func TestLcovReportParsing(t *testing.T) {
+	data := &model.Data{}
+
+	contents := `TN:
+SF:src/lib/math.js
+FN:1,add
+FNDA:2,add
+DA:1,2
+DA:2,2
+DA:3,0
+DA:4,1
+BRDA:2,0,0,2
+BRDA:2,0,1,0
+BRDA:3,1,0,-
+BRDA:3,1,1,-
+LF:4
+LH:3
+BRF:4
+BRH:1
+end_of_record
+SF:src/index.js
+DA:1,1
+end_of_record
+SF:src/lib/strings.js
+DA:1,0
+`
+
+	err := ParseCoverage(data, bytes.NewReader([]byte(contents)))
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, 66.67, data.SuiteResult.Coverage)
+	require.Equal(t, 25.0, data.SuiteResult.BranchCoverage)
+	require.Equal(t, 2, len(data.SuiteResult.Packages))
+
+	pkg := data.SuiteResult.Packages[0]
+	require.Equal(t, "src/lib", pkg.Name)
+	require.Equal(t, uint(3), pkg.LinesCovered)
+	require.Equal(t, uint(5), pkg.LinesValid)
+	require.Equal(t, uint(1), pkg.BranchesCovered)
+	require.Equal(t, uint(4), pkg.BranchesValid)
+	require.Equal(t, 2, len(pkg.Files))
+	require.Equal(t, "math.js", pkg.Files[0].Name)
+	require.Equal(t, 75.0, pkg.Files[0].LineCoverage)
+	require.Equal(t, 25.0, pkg.Files[0].BranchCoverage)
+	require.Equal(t, "strings.js", pkg.Files[1].Name)
+
+	require.Equal(t, "src", data.SuiteResult.Packages[1].Name)
+	require.Equal(t, 100.0, data.SuiteResult.Packages[1].LineCoverage)
+}
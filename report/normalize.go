@@ -0,0 +1,143 @@
+package report
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"treco/conf"
+	"treco/model"
+)
+
+// NameNormalizationRules is the environment variable with semicolon separated regular expressions, which map names of
+// parameterized tests to their base scenario. A rule matches the whole test name, its name group is the scenario name
+// and its params group is stored as the parameters of the result, e.g. ^(?P<name>.+?)\[(?P<params>.*)\]$
+// Default rules are used when not set, and no names are changed when set to none
+const NameNormalizationRules = "NAME_NORMALIZATION_RULES"
+
+var (
+	// Default rules for testLogin[chrome-1] style names of JUnit 5, TestNG, pytest and allure, and for go subtests
+	defaultNameNormalizationRules = []string{
+		`^(?P<name>.+?)\[(?P<params>[^\[\]]*)\]$`,
+		`^(?P<name>(Test|Benchmark|Example|Fuzz)\w*)/(?P<params>.+)$`,
+	}
+)
+
+// normalizeNames replaces names of parameterized tests from index from on with the name of their scenario, parameters
+// already set by the parser are kept. Results of a parent, e.g. TestX of go subtests TestX/case_1, summarise the
+// results of its parameterized tests and are dropped, unless failed while none of the parameterized tests failed
+func normalizeNames(suiteResult *model.SuiteResult, from int) {
+	rules := nameNormalizationRules()
+	if len(rules) == 0 {
+		return
+	}
+
+	type scenario struct{ name, class string }
+	parameterized := make(map[scenario]bool)
+	failed := make(map[scenario]bool)
+	renamed := make([]bool, len(suiteResult.ScenarioResults))
+
+	for i := from; i < len(suiteResult.ScenarioResults); i++ {
+		sr := &suiteResult.ScenarioResults[i]
+		for _, rule := range rules {
+			name, params, ok := normalizeName(rule, sr.Name)
+			if !ok {
+				continue
+			}
+
+			sr.Name = name
+			if sr.Parameters == "" {
+				sr.Parameters = params
+			}
+
+			key := scenario{sr.Name, sr.Class}
+			parameterized[key] = true
+			failed[key] = failed[key] || sr.Status == FAILED
+			renamed[i] = true
+
+			break
+		}
+	}
+
+	kept := suiteResult.ScenarioResults[:from]
+	for i := from; i < len(suiteResult.ScenarioResults); i++ {
+		sr := suiteResult.ScenarioResults[i]
+		key := scenario{sr.Name, sr.Class}
+		if !renamed[i] && parameterized[key] && (sr.Status != FAILED || failed[key]) {
+			removeFromTotals(suiteResult, sr)
+			continue
+		}
+
+		kept = append(kept, sr)
+	}
+
+	suiteResult.ScenarioResults = kept
+}
+
+// removeFromTotals reverts counting of a result by AddScenarioResult, totals of results which were not counted are
+// kept at zero
+func removeFromTotals(suiteResult *model.SuiteResult, scenarioResult model.ScenarioResult) {
+	decrement(&suiteResult.TotalExecuted)
+
+	switch scenarioResult.Status {
+	case FAILED:
+		decrement(&suiteResult.TotalFailed)
+	case SKIPPED:
+		decrement(&suiteResult.TotalSkipped)
+	case FLAKY:
+		decrement(&suiteResult.TotalFlaky)
+	default:
+		decrement(&suiteResult.TotalPassed)
+	}
+}
+
+func decrement(total *uint) {
+	if *total > 0 {
+		*total--
+	}
+}
+
+// normalizeName applies the rule to name, names which are not matched or which would end up empty are not changed
+func normalizeName(rule *regexp.Regexp, name string) (string, string, bool) {
+	m := rule.FindStringSubmatch(name)
+	if m == nil {
+		return "", "", false
+	}
+
+	base := strings.TrimSpace(m[rule.SubexpIndex("name")])
+	if base == "" {
+		return "", "", false
+	}
+
+	return base, m[rule.SubexpIndex("params")], true
+}
+
+// nameNormalizationRules compiles the configured rules, rules which are invalid or miss the name or params group are
+// skipped
+func nameNormalizationRules() []*regexp.Regexp {
+	patterns := defaultNameNormalizationRules
+	if value := strings.TrimSpace(conf.Get(NameNormalizationRules)); value != "" {
+		if strings.EqualFold(value, "none") {
+			return nil
+		}
+
+		patterns = strings.Split(value, ";")
+	}
+
+	rules := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		rule, err := regexp.Compile(pattern)
+		if err != nil || rule.SubexpIndex("name") < 0 || rule.SubexpIndex("params") < 0 {
+			log.Printf("skipping invalid name normalization rule %v\n", pattern)
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
@@ -0,0 +1,108 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"treco/conf"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nolint: scopelint
+func TestNormalizeNames(t *testing.T) {
+	testData := []struct {
+		rules      string
+		name       string
+		parameters string
+		expected   string
+		params     string
+	}{
+		{"", "testLogin[chrome-1]", "", "testLogin", "chrome-1"},
+		{"", "test_divide[1-0-ZeroDivisionError]", "", "test_divide", "1-0-ZeroDivisionError"},
+		{"", "testLogin[chrome, 1]", "chrome, 1", "testLogin", "chrome, 1"},
+		{"", "TestParse/case_7", "", "TestParse", "case_7"},
+		{"", "TestParse/nested/case_7", "", "TestParse", "nested/case_7"},
+		{"", "TestParse", "", "TestParse", ""},
+		{"", "login/logout", "", "login/logout", ""},
+		{"", "[chrome]", "", "[chrome]", ""},
+		{"none", "testLogin[chrome-1]", "", "testLogin[chrome-1]", ""},
+		{`^(?P<name>.+?)\((?P<params>.*)\)$;invalid(`, "Add(1,2)", "", "Add", "1,2"},
+		{`^(?P<name>.+?)\((?P<params>.*)\)$`, "testLogin[chrome-1]", "", "testLogin[chrome-1]", ""},
+		{`^(.+)\[(.*)\]$`, "testLogin[chrome-1]", "", "testLogin[chrome-1]", ""},
+	}
+
+	defer conf.Set(NameNormalizationRules, "")
+
+	for _, data := range testData {
+		t.Run(data.rules+" "+data.name, func(t *testing.T) {
+			conf.Set(NameNormalizationRules, data.rules)
+
+			suiteResult := &model.SuiteResult{
+				ScenarioResults: []model.ScenarioResult{{Name: data.name, Parameters: data.parameters}},
+			}
+			normalizeNames(suiteResult, 0)
+			require.Equal(t, data.expected, suiteResult.ScenarioResults[0].Name)
+			require.Equal(t, data.params, suiteResult.ScenarioResults[0].Parameters)
+		})
+	}
+}
+
+func TestParseNormalizesNames(t *testing.T) {
+	data := &model.Data{}
+
+	contents := `<testsuite tests="2">
+		<testcase name="testLogin[chrome]" classname="com.app.LoginTest"/>
+		<testcase name="testLogin[firefox]" classname="com.app.LoginTest"/>
+	</testsuite>`
+
+	err := Parse(strings.NewReader(contents), data)
+	require.NoError(t, err)
+
+	// Results of an earlier report are not normalized again
+	data.SuiteResult.ScenarioResults[1].Name = "testLogin[firefox]"
+	err = Parse(strings.NewReader(`<testsuite tests="1"><testcase name="testLogout[chrome]" classname="com.app.LoginTest"/></testsuite>`), data)
+	require.NoError(t, err)
+
+	results := data.SuiteResult.ScenarioResults
+	require.Equal(t, "testLogin", results[0].Name)
+	require.Equal(t, "chrome", results[0].Parameters)
+	require.Equal(t, "testLogin[firefox]", results[1].Name)
+	require.Equal(t, "testLogout", results[2].Name)
+	require.Equal(t, "chrome", results[2].Parameters)
+}
+
+func TestParseDropsParentsOfSubtests(t *testing.T) {
+	data := &model.Data{}
+
+	contents := `<testsuite tests="7" failures="2">
+		<testcase name="TestParse" classname="app/parser"/>
+		<testcase name="TestParse/case_1" classname="app/parser"/>
+		<testcase name="TestParse/case_2" classname="app/parser"/>
+		<testcase name="TestLoad" classname="app/parser"><failure/></testcase>
+		<testcase name="TestLoad/case_1" classname="app/parser"/>
+		<testcase name="TestSave" classname="app/parser"><failure/></testcase>
+		<testcase name="TestSave/case_1" classname="app/parser"><failure/></testcase>
+	</testsuite>`
+
+	err := Parse(strings.NewReader(contents), data)
+	require.NoError(t, err)
+
+	suiteResult := data.SuiteResult
+	require.Equal(t, uint(5), suiteResult.TotalExecuted)
+	require.Equal(t, uint(3), suiteResult.TotalPassed)
+	require.Equal(t, uint(2), suiteResult.TotalFailed)
+
+	results := suiteResult.ScenarioResults
+	require.Len(t, results, 5)
+	require.Equal(t, "TestParse", results[0].Name)
+	require.Equal(t, "case_1", results[0].Parameters)
+	require.Equal(t, "case_2", results[1].Parameters)
+	// Parent failing on its own, e.g. in cleanup, is kept
+	require.Equal(t, "TestLoad", results[2].Name)
+	require.Equal(t, "", results[2].Parameters)
+	require.Equal(t, FAILED, results[2].Status)
+	require.Equal(t, "case_1", results[3].Parameters)
+	require.Equal(t, "TestSave", results[4].Name)
+	require.Equal(t, "case_1", results[4].Parameters)
+}
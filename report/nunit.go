@@ -0,0 +1,116 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"treco/model"
+)
+
+// NUnitTestRun struct
+type NUnitTestRun struct {
+	XMLName    xml.Name         `xml:"test-run"`
+	Duration   float64          `xml:"duration,attr"`
+	TestSuites []NUnitTestSuite `xml:"test-suite"`
+}
+
+// NUnitTestSuite struct, suites can be nested to any depth (assembly, namespace, fixture etc.)
+type NUnitTestSuite struct {
+	ClassName  string           `xml:"classname,attr"`
+	Properties []NUnitProperty  `xml:"properties>property"`
+	TestSuites []NUnitTestSuite `xml:"test-suite"`
+	TestCases  []NUnitTestCase  `xml:"test-case"`
+}
+
+// NUnitTestCase struct
+type NUnitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Result     string          `xml:"result,attr"`
+	Duration   float64         `xml:"duration,attr"`
+	Properties []NUnitProperty `xml:"properties>property"`
+}
+
+// NUnitProperty struct
+type NUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+var (
+	errUnableToUnmarshalToNUnit = "unmarshalling to nunit3 failed"
+)
+
+type nUnit3XMLParser struct{}
+
+func init() {
+	Register("nunit3", nUnit3XMLParser{})
+}
+
+func (nUnit3XMLParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	run := NUnitTestRun{}
+
+	log.Println("unmarshalling to nunit3 report")
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return fmt.Errorf(errUnableToUnmarshalToNUnit)
+	}
+
+	suiteResult.TimeTaken += run.Duration
+
+	for _, suite := range run.TestSuites {
+		addNUnitTestSuite(suiteResult, suite, nil)
+	}
+
+	return nil
+}
+
+// addNUnitTestSuite walks the suite tree, categories of parent suites apply to all the tests underneath
+func addNUnitTestSuite(suiteResult *model.SuiteResult, suite NUnitTestSuite, categories []string) {
+	categories = append(categories[:len(categories):len(categories)], nUnitCategories(suite.Properties)...)
+
+	for _, child := range suite.TestSuites {
+		addNUnitTestSuite(suiteResult, child, categories)
+	}
+
+	for _, tc := range suite.TestCases {
+		status := PASSED
+		switch strings.ToLower(tc.Result) {
+		case "failed":
+			status = FAILED
+		case "skipped", "inconclusive":
+			status = SKIPPED
+		}
+
+		class := tc.ClassName
+		if class == "" {
+			class = suite.ClassName
+		}
+
+		tags := append(categories[:len(categories):len(categories)], nUnitCategories(tc.Properties)...)
+
+		AddScenarioResult(suiteResult, model.ScenarioResult{
+			Name:      tc.Name,
+			Class:     class,
+			Status:    status,
+			TimeTaken: tc.Duration,
+			Features:  tags,
+			Tags:      tags,
+		})
+	}
+}
+
+// nUnitCategories returns values of category properties
+func nUnitCategories(properties []NUnitProperty) []string {
+	categories := make([]string, 0)
+	for _, property := range properties {
+		if strings.EqualFold(property.Name, "Category") {
+			categories = append(categories, property.Value)
+		}
+	}
+
+	return categories
+}
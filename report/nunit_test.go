@@ -0,0 +1,72 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidNUnit3Content(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "nunit3",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToNUnit), err)
+}
+
+func TestNUnit3ReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "nunit3",
+	}
+
+	contents := `
+	<?xml version="1.0" encoding="utf-8"?>
+	<test-run id="0" testcasecount="4" result="Failed" total="4" passed="1" failed="1" skipped="2" duration="2.5">
+		<test-suite type="Assembly" name="App.Tests.dll" result="Failed">
+			<test-suite type="TestSuite" name="App" result="Failed">
+				<test-suite type="TestFixture" name="LoginTests" classname="App.LoginTests" result="Failed">
+					<properties>
+						<property name="Category" value="smoke" />
+					</properties>
+					<test-case name="ValidLogin" classname="App.LoginTests" result="Passed" duration="1.2">
+						<properties>
+							<property name="Category" value="PROJ-7" />
+							<property name="Description" value="logs in" />
+						</properties>
+					</test-case>
+					<test-case name="InvalidLogin" classname="App.LoginTests" result="Failed" label="Error" duration="0.8" />
+					<test-case name="Logout" classname="App.LoginTests" result="Skipped" label="Ignored" duration="0" />
+				</test-suite>
+				<test-suite type="ParameterizedMethod" name="Add" classname="App.MathTests" result="Skipped">
+					<test-case name="Add(1,2)" result="Inconclusive" duration="0.5" />
+				</test-suite>
+			</test-suite>
+		</test-suite>
+	</test-run>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(4), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(2), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 2.5, data.SuiteResult.TimeTaken, 0.0001)
+	require.Equal(t, 4, len(data.SuiteResult.ScenarioResults))
+
+	validLogin := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "ValidLogin", validLogin.Name)
+	require.Equal(t, "App.LoginTests", validLogin.Class)
+	require.Equal(t, PASSED, validLogin.Status)
+	require.Equal(t, []string{"smoke", "PROJ-7"}, validLogin.Tags)
+	require.Equal(t, []string{"smoke"}, data.SuiteResult.ScenarioResults[1].Tags)
+
+	add := data.SuiteResult.ScenarioResults[3]
+	require.Equal(t, "Add(1,2)", add.Name)
+	require.Equal(t, "App.MathTests", add.Class)
+	require.Equal(t, SKIPPED, add.Status)
+}
@@ -4,35 +4,210 @@ Package report handles parsing of different report types
 package report
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"log"
+	"strconv"
 	"strings"
+	"treco/conf"
 	"treco/model"
+	"unicode/utf8"
 )
 
+// MaxOutputSize is the environment variable for maximum bytes stored for each message, stack trace or output of a test
+const MaxOutputSize = "MAX_OUTPUT_SIZE"
+
+const defaultMaxOutputSize = 64 * 1024
+
 var (
-	errInvalidReportType = "invalid report type: %v"
+	errInvalidReportType    = "invalid report type: %v"
+	errReportFormatMismatch = "%w, report looks like %v instead of %v"
+	errNoReportsInArchive   = "no reports found in archive"
 )
 
-// Parser interface
-type Parser interface {
-	parse(r io.Reader, result *model.Data) error
+// Parse parses data from provided reader, report format is detected from the contents when not set.
+// Gzip or zstd compressed reports are decompressed, and zip or tar archives are expanded with every report in them
+// added to the same suite result. Names of parameterized tests are normalized to their scenario name
+func Parse(r io.Reader, data *model.Data) error {
+	br, err := decompress(bufio.NewReaderSize(r, detectPeekSize))
+	if err != nil {
+		return err
+	}
+
+	// Results of earlier reports are already normalized
+	parsed := len(data.SuiteResult.ScenarioResults)
+
+	if isArchive(br) {
+		err = parseArchive(br, data)
+	} else {
+		err = parseReport(br, data, detectFormat(br))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	normalizeNames(&data.SuiteResult, parsed)
+	return nil
 }
 
-// Parse parses data from provided reader
-func Parse(r io.Reader, data *model.Data) error {
-	var parser Parser
+// parseArchive parses each report in the archive, files of which format is unknown or different to the one set are skipped
+func parseArchive(r io.Reader, data *model.Data) error {
+	rf := strings.ToLower(data.ReportFormat)
+	allure := newAllureResults()
+	reports := 0
+
+	log.Println("reading reports from archive")
+	err := readArchive(r, func(name string, f io.Reader) error {
+		// Allure writes one file per test, which are combined once the whole archive is read
+		if isAllureResult(name) && (rf == "" || rf == "allure") {
+			reports++
+			return allure.add(name, f)
+		}
+
+		br, err := decompress(bufio.NewReaderSize(f, detectPeekSize))
+		if err != nil {
+			return err
+		}
+
+		// Files of a format which cannot be detected are parsed with the format set
+		detected := detectFormat(br)
+		if detected == "" && (rf == "" || isDetectable(rf)) {
+			log.Printf("skipping %v from archive, unknown report format\n", name)
+			return nil
+		}
+
+		if rf != "" && detected != "" && detected != rf {
+			log.Printf("skipping %v from archive, not a %v report\n", name, rf)
+			return nil
+		}
+
+		reports++
+		return parseReport(br, data, detected)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(allure.order) > 0 {
+		addReportFormat(&data.SuiteResult, "allure")
+		return allure.addTo(&data.SuiteResult)
+	}
+
+	if reports == 0 {
+		return fmt.Errorf(errNoReportsInArchive)
+	}
+
+	return nil
+}
+
+// parseReport parses a single report with the format set in data, or with the detected one when not set
+func parseReport(r io.Reader, data *model.Data, detected string) error {
 	var err error
 
 	rf := strings.ToLower(data.ReportFormat)
+	if rf == "" {
+		if detected == "" {
+			return fmt.Errorf(errUnableToDetectReportFormat)
+		}
 
-	switch rf {
-	case "junit":
-		parser = junitXMLParser{}
-		err = parser.parse(r, data)
-	default:
+		log.Printf("detected report format %v\n", detected)
+		rf = detected
+	}
+
+	addReportFormat(&data.SuiteResult, rf)
+
+	if parser, ok := registeredParser(rf); ok {
+		// Parsers report unreadable data as invalid, the read error e.g. of a report over the size limit is returned instead
+		er := &errorRecordingReader{r: r}
+		err = parser.Parse(er, data)
+		if er.err != nil {
+			return er.err
+		}
+	} else {
 		err = fmt.Errorf(errInvalidReportType, rf)
 	}
 
+	// Hint the likely format when report was sent with a wrong one
+	if err != nil && detected != "" && detected != rf {
+		err = fmt.Errorf(errReportFormatMismatch, err, detected, rf)
+	}
+
 	return err
 }
+
+// errorRecordingReader keeps the first error other than io.EOF returned by the reader
+type errorRecordingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errorRecordingReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err != nil && err != io.EOF && e.err == nil {
+		e.err = err
+	}
+
+	return n, err
+}
+
+// addReportFormat records format on suite result, formats are comma separated when reports of different formats are sent
+func addReportFormat(suiteResult *model.SuiteResult, rf string) {
+	for _, f := range strings.Split(suiteResult.ReportFormat, ",") {
+		if f == rf {
+			return
+		}
+	}
+
+	if suiteResult.ReportFormat != "" {
+		rf = suiteResult.ReportFormat + "," + rf
+	}
+
+	suiteResult.ReportFormat = rf
+}
+
+// AddScenarioResult appends scenario result to the suite and updates suite totals based on its status. Parsers add
+// results with it, so that totals stay consistent with the results
+func AddScenarioResult(suiteResult *model.SuiteResult, scenarioResult model.ScenarioResult) {
+	suiteResult.TotalExecuted++
+
+	switch scenarioResult.Status {
+	case FAILED:
+		suiteResult.TotalFailed++
+	case SKIPPED:
+		suiteResult.TotalSkipped++
+	case FLAKY:
+		suiteResult.TotalFlaky++
+	default:
+		suiteResult.TotalPassed++
+	}
+
+	scenarioResult.SuiteResultID = suiteResult.ID
+	suiteResult.ScenarioResults = append(suiteResult.ScenarioResults, scenarioResult)
+}
+
+// outputSizeLimit returns the configured maximum output size, falling back to the default when not set or invalid
+func outputSizeLimit() int {
+	limit, err := strconv.Atoi(conf.Get(MaxOutputSize))
+	if err != nil || limit <= 0 {
+		return defaultMaxOutputSize
+	}
+
+	return limit
+}
+
+// truncate trims whitespace around s and cuts it to at most limit bytes without splitting a character
+func truncate(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= limit {
+		return s
+	}
+
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+
+	return s[:limit]
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
+	"treco/conf"
 	"treco/model"
 
 	"github.com/stretchr/testify/require"
@@ -58,8 +60,346 @@ func TestJunitReportParsing(t *testing.T) {
 	err := Parse(bytes.NewReader([]byte(contents)), data)
 	require.NoError(t, err, "Paring error")
 	require.Equal(t, data.SuiteResult.TotalExecuted, uint(5))
-	require.Equal(t, data.SuiteResult.TotalFailed, uint(2))
+	// failed element is not a failure, hence totals of test cases differ from the attributes
+	require.Equal(t, data.SuiteResult.TotalFailed, uint(1))
 	require.Equal(t, data.SuiteResult.TotalSkipped, uint(1))
-	require.Equal(t, data.SuiteResult.TotalPassed, uint(2))
+	require.Equal(t, data.SuiteResult.TotalPassed, uint(3))
+	require.Equal(t, data.SuiteResult.TotalsMismatch, true)
 	require.Equal(t, len(data.SuiteResult.ScenarioResults), 5)
 }
+
+func TestJunitReportWithTestSuites(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `
+	<?xml version="1.0" encoding="UTF-8"?>
+	<testsuites>
+		<testsuite name="suite.one" tests="2" failures="1" time="1.5">
+			<testcase name="test_passed" time="0.5" classname="suite.one"/>
+			<testcase name="test_failed" time="1.0" classname="suite.one"><failure message="boom"/></testcase>
+		</testsuite>
+		<testsuite name="suite.two" tests="1" skipped="1" time="0">
+			<testcase name="test_skipped" classname="suite.two"><skipped/></testcase>
+		</testsuite>
+	</testsuites>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(3), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 1.5, data.SuiteResult.TimeTaken, 0.0001)
+	require.Equal(t, 3, len(data.SuiteResult.ScenarioResults))
+	require.Equal(t, SKIPPED, data.SuiteResult.ScenarioResults[2].Status)
+}
+
+func TestJunitReportWithTestSuitesInTestName(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `
+	<testsuite name="parser" tests="1" time="0.1">
+		<testcase name="parses testsuites element" time="0.1" classname="parser"/>
+	</testsuite>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+	require.Equal(t, "parses testsuites element", data.SuiteResult.ScenarioResults[0].Name)
+}
+
+func TestInvalidJunitRootElement(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `<testng-results><suite/></testng-results>`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Error(t, err)
+}
+
+func TestJunitFailureDetails(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `
+	<testsuite name="suite" tests="3" failures="1" errors="1" skipped="1" time="1">
+		<testcase name="test_failed" classname="suite">
+			<failure message="expected 1 but was 2" type="AssertionError">at suite.test_failed(Suite.java:10)</failure>
+			<system-out>some output</system-out>
+			<system-err>some error output</system-err>
+		</testcase>
+		<testcase name="test_error" classname="suite">
+			<error message="null" type="NullPointerException"><![CDATA[java.lang.NullPointerException
+	at suite.test_error(Suite.java:20)]]></error>
+		</testcase>
+		<testcase name="test_skipped" classname="suite"><skipped message="not ready"/></testcase>
+	</testsuite>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+
+	failed := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "expected 1 but was 2", failed.Message)
+	require.Equal(t, "AssertionError", failed.FailureType)
+	require.Equal(t, "at suite.test_failed(Suite.java:10)", failed.StackTrace)
+	require.Equal(t, "some output", failed.SystemOut)
+	require.Equal(t, "some error output", failed.SystemErr)
+
+	errored := data.SuiteResult.ScenarioResults[1]
+	require.Equal(t, "NullPointerException", errored.FailureType)
+	require.Equal(t, "java.lang.NullPointerException\n\tat suite.test_error(Suite.java:20)", errored.StackTrace)
+
+	require.Equal(t, "not ready", data.SuiteResult.ScenarioResults[2].Message)
+}
+
+func TestJunitOutputSizeLimit(t *testing.T) {
+	conf.Set(MaxOutputSize, "10")
+	defer conf.Set(MaxOutputSize, "")
+
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `
+	<testsuite name="suite" tests="1" failures="1">
+		<testcase name="test_failed" classname="suite">
+			<failure message="short">a very long stack trace</failure>
+			<system-out>ééééééé</system-out>
+		</testcase>
+	</testsuite>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+
+	failed := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "short", failed.Message)
+	require.Equal(t, "a very lon", failed.StackTrace)
+	require.Equal(t, "ééééé", failed.SystemOut)
+}
+
+func TestJunitProperties(t *testing.T) {
+	conf.Set(FeatureProperty, "requirement, jira")
+	defer conf.Set(FeatureProperty, "")
+
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `
+	<testsuites>
+		<testsuite name="suite" tests="2" time="1">
+			<properties>
+				<property name="java.version" value="17"/>
+				<property name="jira" value="PROJ-1"/>
+			</properties>
+			<testcase name="test_one" classname="suite">
+				<properties>
+					<property name="requirement" value="PROJ-2, PROJ-3"/>
+					<property name="owner">team-a</property>
+				</properties>
+			</testcase>
+			<testcase name="test_two" classname="suite"/>
+		</testsuite>
+		<testsuite name="other" tests="1" time="1">
+			<properties>
+				<property name="java.version" value="17"/>
+				<property name="jira" value="PROJ-4"/>
+			</properties>
+			<testcase name="test_three" classname="other"/>
+		</testsuite>
+	</testsuites>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+
+	require.Equal(t, []model.SuiteResultProperty{
+		{Name: "java.version", Value: "17"},
+		{Name: "jira", Value: "PROJ-1"},
+		{Name: "jira", Value: "PROJ-4"},
+	}, data.SuiteResult.Properties)
+
+	testOne := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, []model.ScenarioResultProperty{
+		{Name: "requirement", Value: "PROJ-2, PROJ-3"},
+		{Name: "owner", Value: "team-a"},
+	}, testOne.Properties)
+	require.Subset(t, testOne.Features, []string{"PROJ-1", "PROJ-2", "PROJ-3"})
+
+	require.Contains(t, data.SuiteResult.ScenarioResults[1].Features, "PROJ-1")
+	require.NotContains(t, data.SuiteResult.ScenarioResults[2].Features, "PROJ-1")
+}
+
+func TestJunitNestedTestSuites(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `
+	<testsuites>
+		<testsuite name="src/login.test.js" hostname="ci-runner-1" timestamp="2023-08-01T10:00:00">
+			<testsuite name="login" tests="3" failures="1" time="1.75">
+				<testcase name="logs in" classname="login" time="0.5"/>
+				<testcase name="rejects bad password" classname="login" time="1"><failure/></testcase>
+				<testsuite name="remember me" tests="1" time="0.25" timestamp="2023-08-01T10:00:01Z">
+					<testcase name="keeps session" classname="login.remember" time="0.25"/>
+				</testsuite>
+			</testsuite>
+			<testsuite name="logout" tests="1" skipped="1" time="0">
+				<testcase name="logs out" classname="logout"><skipped/></testcase>
+			</testsuite>
+		</testsuite>
+		<testsuite name="src/home.test.js" tests="1" time="0.5">
+			<testcase name="renders" classname="home" time="0.5"/>
+		</testsuite>
+	</testsuites>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, 5, len(data.SuiteResult.ScenarioResults))
+	require.Equal(t, uint(5), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(3), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 2.25, data.SuiteResult.TimeTaken, 0.0001)
+
+	results := data.SuiteResult.ScenarioResults
+	require.Equal(t, "login", results[0].SuiteName)
+	require.Equal(t, "src/login.test.js > login", results[0].SuitePath)
+	require.Equal(t, "ci-runner-1", results[0].Hostname)
+	require.Equal(t, time.Date(2023, 8, 1, 10, 0, 0, 0, time.UTC), *results[0].Timestamp)
+
+	require.Equal(t, "keeps session", results[2].Name)
+	require.Equal(t, "src/login.test.js > login > remember me", results[2].SuitePath)
+	require.Equal(t, time.Date(2023, 8, 1, 10, 0, 1, 0, time.UTC), *results[2].Timestamp)
+
+	require.Equal(t, "src/login.test.js > logout", results[3].SuitePath)
+	require.Equal(t, "src/home.test.js", results[4].SuitePath)
+	require.Empty(t, results[4].Hostname)
+	require.Nil(t, results[4].Timestamp)
+}
+
+func TestJunitFlakyAndRerunFailures(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "junit",
+	}
+
+	contents := `
+	<testsuite name="com.app.LoginTest" tests="4" failures="1" errors="0" skipped="0" flakes="2" time="3">
+		<testcase name="testLogin" classname="com.app.LoginTest" time="1">
+			<flakyFailure message="timed out" type="java.util.concurrent.TimeoutException">
+				<stackTrace>java.util.concurrent.TimeoutException: timed out</stackTrace>
+				<system-out>first attempt</system-out>
+			</flakyFailure>
+			<flakyError message="connection reset" type="java.net.SocketException">
+				<stackTrace>java.net.SocketException: connection reset</stackTrace>
+			</flakyError>
+		</testcase>
+		<testcase name="testLogout" classname="com.app.LoginTest" time="0.5">
+			<flakyFailure message="stale element" type="StaleElementReferenceException"/>
+		</testcase>
+		<testcase name="testSignup" classname="com.app.LoginTest" time="1">
+			<failure message="expected 200 but was 500" type="AssertionError">at com.app.LoginTest.testSignup</failure>
+			<rerunFailure message="expected 200 but was 500" type="AssertionError">
+				<stackTrace>at com.app.LoginTest.testSignup</stackTrace>
+			</rerunFailure>
+		</testcase>
+		<testcase name="testHome" classname="com.app.LoginTest" time="0.5"/>
+	</testsuite>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(4), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(2), data.SuiteResult.TotalFlaky)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+
+	results := data.SuiteResult.ScenarioResults
+	require.Equal(t, FLAKY, results[0].Status)
+	require.Equal(t, uint(3), results[0].Attempts)
+	require.Equal(t, "timed out", results[0].Message)
+	require.Equal(t, "java.util.concurrent.TimeoutException", results[0].FailureType)
+	require.Equal(t, "java.util.concurrent.TimeoutException: timed out", results[0].StackTrace)
+
+	require.Equal(t, FLAKY, results[1].Status)
+	require.Equal(t, uint(2), results[1].Attempts)
+
+	require.Equal(t, FAILED, results[2].Status)
+	require.Equal(t, uint(2), results[2].Attempts)
+	require.Equal(t, "expected 200 but was 500", results[2].Message)
+
+	require.Equal(t, PASSED, results[3].Status)
+	require.Equal(t, uint(1), results[3].Attempts)
+}
+
+// nolint: scopelint
+func TestJunitTotalsReconciliation(t *testing.T) {
+	testData := []struct {
+		testName string
+		contents string
+		executed uint
+		passed   uint
+		failed   uint
+		skipped  uint
+		mismatch bool
+	}{
+		{
+			testName: "missing attributes",
+			contents: `<testsuite name="suite">
+				<testcase name="test_passed"/>
+				<testcase name="test_failed"><failure/></testcase>
+				<testcase name="test_skipped"><skipped/></testcase>
+			</testsuite>`,
+			executed: 3, passed: 1, failed: 1, skipped: 1,
+		},
+		{
+			testName: "failures more than tests",
+			contents: `<testsuite name="suite" tests="1" failures="2">
+				<testcase name="test_failed"><failure/></testcase>
+			</testsuite>`,
+			executed: 1, failed: 1, mismatch: true,
+		},
+		{
+			testName: "summary without test cases",
+			contents: `<testsuites><testsuite name="suite" tests="4" failures="1" errors="1" skipped="1"/></testsuites>`,
+			executed: 4, passed: 1, failed: 2, skipped: 1,
+		},
+		{
+			testName: "summary with more failures than tests",
+			contents: `<testsuite name="suite" tests="1" failures="1" skipped="1"/>`,
+			executed: 1, failed: 1, skipped: 1, mismatch: true,
+		},
+		{
+			testName: "consistent nested suites",
+			contents: `<testsuites>
+				<testsuite name="outer">
+					<testsuite name="inner" tests="2" errors="1">
+						<testcase name="test_passed"/>
+						<testcase name="test_error"><error/></testcase>
+					</testsuite>
+				</testsuite>
+			</testsuites>`,
+			executed: 2, passed: 1, failed: 1,
+		},
+	}
+
+	for _, data := range testData {
+		t.Run(data.testName, func(t *testing.T) {
+			result := &model.Data{ReportFormat: "junit"}
+			err := Parse(bytes.NewReader([]byte(data.contents)), result)
+			require.NoError(t, err, "Parsing error")
+
+			suiteResult := result.SuiteResult
+			require.Equal(t, data.executed, suiteResult.TotalExecuted)
+			require.Equal(t, data.passed, suiteResult.TotalPassed)
+			require.Equal(t, data.failed, suiteResult.TotalFailed)
+			require.Equal(t, data.skipped, suiteResult.TotalSkipped)
+			require.Equal(t, data.mismatch, suiteResult.TotalsMismatch)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+	"treco/model"
+)
+
+// PlaywrightReport struct, output of the playwright json reporter
+type PlaywrightReport struct {
+	Suites []PlaywrightSuite `json:"suites"`
+}
+
+// PlaywrightSuite struct, either a test file or a describe block
+type PlaywrightSuite struct {
+	Title  string            `json:"title"`
+	File   string            `json:"file"`
+	Specs  []PlaywrightSpec  `json:"specs"`
+	Suites []PlaywrightSuite `json:"suites"`
+}
+
+// PlaywrightSpec struct, a test with one run per project
+type PlaywrightSpec struct {
+	Title string           `json:"title"`
+	File  string           `json:"file"`
+	Tags  []string         `json:"tags"`
+	Tests []PlaywrightTest `json:"tests"`
+}
+
+// PlaywrightTest struct, results has an entry for each attempt
+type PlaywrightTest struct {
+	ProjectName    string                 `json:"projectName"`
+	ExpectedStatus string                 `json:"expectedStatus"`
+	Status         string                 `json:"status"`
+	Annotations    []PlaywrightAnnotation `json:"annotations"`
+	Results        []PlaywrightResult     `json:"results"`
+}
+
+// PlaywrightAnnotation struct
+type PlaywrightAnnotation struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// PlaywrightResult struct, duration is in milliseconds
+type PlaywrightResult struct {
+	Status    string            `json:"status"`
+	Duration  float64           `json:"duration"`
+	StartTime string            `json:"startTime"`
+	Error     *PlaywrightError  `json:"error"`
+	Stdout    []PlaywrightChunk `json:"stdout"`
+	Stderr    []PlaywrightChunk `json:"stderr"`
+}
+
+// PlaywrightError struct
+type PlaywrightError struct {
+	Message string `json:"message"`
+	Stack   string `json:"stack"`
+}
+
+// PlaywrightChunk struct, output written as binary is not stored
+type PlaywrightChunk struct {
+	Text string `json:"text"`
+}
+
+var (
+	errUnableToUnmarshalToPlaywright = "unmarshalling to playwright failed"
+
+	// Playwright joins titles of describe blocks and test with this separator
+	playwrightTitleSeparator = " › "
+)
+
+type playwrightJSONParser struct{}
+
+func init() {
+	Register("playwright", playwrightJSONParser{})
+}
+
+func (playwrightJSONParser) Parse(r io.Reader, result *model.Data) error {
+	report := PlaywrightReport{}
+
+	log.Println("unmarshalling to playwright report")
+	if err := json.NewDecoder(r).Decode(&report); err != nil || report.Suites == nil {
+		return fmt.Errorf(errUnableToUnmarshalToPlaywright)
+	}
+
+	limit := outputSizeLimit()
+	for _, suite := range report.Suites {
+		addPlaywrightSuite(&result.SuiteResult, suite, nil, limit)
+	}
+
+	return nil
+}
+
+// addPlaywrightSuite adds specs of the suite and its nested describe blocks, titles holds titles of parent describe blocks
+func addPlaywrightSuite(suiteResult *model.SuiteResult, suite PlaywrightSuite, titles []string, limit int) {
+	for _, spec := range suite.Specs {
+		for _, test := range spec.Tests {
+			addPlaywrightTest(suiteResult, spec, test, titles, limit)
+		}
+	}
+
+	for _, child := range suite.Suites {
+		addPlaywrightSuite(suiteResult, child, append(titles[:len(titles):len(titles)], child.Title), limit)
+	}
+}
+
+// addPlaywrightTest adds a scenario result for the run of a spec in a project, the last attempt decides the result.
+// A test which passed on a retry is flaky
+func addPlaywrightTest(suiteResult *model.SuiteResult, spec PlaywrightSpec, test PlaywrightTest, titles []string, limit int) {
+	last := PlaywrightResult{}
+	if len(test.Results) > 0 {
+		last = test.Results[len(test.Results)-1]
+	}
+
+	status := PASSED
+	switch {
+	case test.Status == "skipped" || last.Status == "skipped":
+		status = SKIPPED
+	case test.Status == "unexpected":
+		status = FAILED
+	case test.Status == "flaky":
+		status = FLAKY
+	}
+
+	timeTaken := last.Duration / 1000
+	suiteResult.TimeTaken += timeTaken
+
+	attempts := uint(len(test.Results))
+	if attempts == 0 {
+		attempts = 1
+	}
+
+	features, tags := playwrightTags(spec.Tags, test.Annotations)
+
+	sr := model.ScenarioResult{
+		Name:      strings.Join(append(titles[:len(titles):len(titles)], spec.Title), playwrightTitleSeparator),
+		Class:     spec.File,
+		Status:    status,
+		TimeTaken: timeTaken,
+		Attempts:  attempts,
+		Features:  features,
+		Tags:      tags,
+		Project:   test.ProjectName,
+		SystemOut: truncate(playwrightOutput(last.Stdout), limit),
+		SystemErr: truncate(playwrightOutput(last.Stderr), limit),
+		SuiteName: spec.File,
+		SuitePath: strings.Join(append([]string{spec.File}, titles...), junitSuitePathSeparator),
+	}
+
+	if last.Error != nil {
+		sr.Message = truncate(firstLine(last.Error.Message), limit)
+		sr.StackTrace = truncate(last.Error.Stack, limit)
+	}
+
+	if t, err := time.Parse(time.RFC3339, last.StartTime); err == nil {
+		sr.Timestamp = &t
+	}
+
+	AddScenarioResult(suiteResult, sr)
+}
+
+// playwrightTags returns issue annotations and spec tags as features, and spec tags and other annotations as tags.
+// Leading '@' of tags is stripped
+func playwrightTags(specTags []string, annotations []PlaywrightAnnotation) ([]string, []string) {
+	features := make([]string, 0)
+	tags := make([]string, 0)
+
+	for _, tag := range specTags {
+		tag = strings.TrimPrefix(tag, "@")
+		features = append(features, tag)
+		tags = append(tags, tag)
+	}
+
+	for _, annotation := range annotations {
+		switch {
+		case annotation.Type == "issue" && annotation.Description != "":
+			// Issue is usually a link, its last path element is the issue key
+			features = append(features, path.Base(annotation.Description))
+		case annotation.Description != "":
+			tags = append(tags, annotation.Type+"="+annotation.Description)
+		default:
+			tags = append(tags, annotation.Type)
+		}
+	}
+
+	return features, tags
+}
+
+// playwrightOutput joins output chunks written by the test
+func playwrightOutput(chunks []PlaywrightChunk) string {
+	var sb strings.Builder
+	for _, chunk := range chunks {
+		sb.WriteString(chunk.Text)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,138 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidPlaywrightContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "playwright",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToPlaywright), err)
+}
+
+func TestPlaywrightReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "playwright",
+	}
+
+	contents := `{
+  "config": {"projects": [{"id": "chromium", "name": "chromium"}, {"id": "firefox", "name": "firefox"}]},
+  "suites": [
+    {
+      "title": "login.spec.ts",
+      "file": "login.spec.ts",
+      "specs": [],
+      "suites": [
+        {
+          "title": "login",
+          "file": "login.spec.ts",
+          "specs": [
+            {
+              "title": "signs in",
+              "file": "login.spec.ts",
+              "tags": ["@smoke", "@DAKOTA-12"],
+              "tests": [
+                {
+                  "projectName": "chromium",
+                  "expectedStatus": "passed",
+                  "status": "expected",
+                  "annotations": [{"type": "issue", "description": "https://jira.example.com/browse/DAKOTA-34"}],
+                  "results": [{"status": "passed", "duration": 1500, "startTime": "2023-08-01T10:00:00.000Z",
+                    "stdout": [{"text": "signing in\n"}], "stderr": []}]
+                },
+                {
+                  "projectName": "firefox",
+                  "expectedStatus": "passed",
+                  "status": "flaky",
+                  "annotations": [{"type": "slow"}],
+                  "results": [
+                    {"status": "failed", "duration": 3000, "startTime": "2023-08-01T10:00:00.000Z",
+                      "error": {"message": "Timeout 5000ms exceeded.\nwaiting for locator", "stack": "Error: Timeout 5000ms exceeded."}},
+                    {"status": "passed", "duration": 2000, "startTime": "2023-08-01T10:00:04.000Z"}
+                  ]
+                }
+              ]
+            }
+          ],
+          "suites": []
+        }
+      ]
+    },
+    {
+      "title": "cart.spec.ts",
+      "file": "cart.spec.ts",
+      "specs": [
+        {
+          "title": "adds item",
+          "file": "cart.spec.ts",
+          "tags": [],
+          "tests": [
+            {
+              "projectName": "chromium",
+              "expectedStatus": "passed",
+              "status": "unexpected",
+              "annotations": [],
+              "results": [{"status": "failed", "duration": 500, "startTime": "2023-08-01T10:00:00.000Z",
+                "error": {"message": "expect(received).toBe(expected)", "stack": "Error: expect(received).toBe(expected)\n    at cart.spec.ts:5:3"}}]
+            },
+            {
+              "projectName": "firefox",
+              "expectedStatus": "skipped",
+              "status": "skipped",
+              "annotations": [{"type": "skip", "description": "not supported"}],
+              "results": []
+            }
+          ]
+        }
+      ]
+    }
+  ],
+  "errors": []
+}`
+
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+
+	suiteResult := data.SuiteResult
+	require.Equal(t, uint(4), suiteResult.TotalExecuted)
+	require.Equal(t, uint(1), suiteResult.TotalPassed)
+	require.Equal(t, uint(1), suiteResult.TotalFlaky)
+	require.Equal(t, uint(1), suiteResult.TotalFailed)
+	require.Equal(t, uint(1), suiteResult.TotalSkipped)
+	require.Equal(t, 4.0, suiteResult.TimeTaken)
+
+	scenarioResults := suiteResult.ScenarioResults
+	require.Equal(t, "login › signs in", scenarioResults[0].Name)
+	require.Equal(t, "login.spec.ts", scenarioResults[0].Class)
+	require.Equal(t, "chromium", scenarioResults[0].Project)
+	require.Equal(t, "login.spec.ts > login", scenarioResults[0].SuitePath)
+	require.Equal(t, []string{"smoke", "DAKOTA-12", "DAKOTA-34"}, scenarioResults[0].Features)
+	require.Equal(t, []string{"smoke", "DAKOTA-12"}, scenarioResults[0].Tags)
+	require.Equal(t, "signing in", scenarioResults[0].SystemOut)
+	require.Equal(t, "2023-08-01T10:00:00Z", scenarioResults[0].Timestamp.Format(time.RFC3339))
+
+	require.Equal(t, "firefox", scenarioResults[1].Project)
+	require.Equal(t, FLAKY, scenarioResults[1].Status)
+	require.Equal(t, uint(2), scenarioResults[1].Attempts)
+	require.Equal(t, "", scenarioResults[1].Message)
+	require.Equal(t, []string{"smoke", "DAKOTA-12", "slow"}, scenarioResults[1].Tags)
+
+	require.Equal(t, "adds item", scenarioResults[2].Name)
+	require.Equal(t, FAILED, scenarioResults[2].Status)
+	require.Equal(t, "expect(received).toBe(expected)", scenarioResults[2].Message)
+	require.Contains(t, scenarioResults[2].StackTrace, "cart.spec.ts:5:3")
+
+	require.Equal(t, SKIPPED, scenarioResults[3].Status)
+	require.Equal(t, uint(1), scenarioResults[3].Attempts)
+	require.Equal(t, []string{"skip=not supported"}, scenarioResults[3].Tags)
+}
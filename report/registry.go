@@ -0,0 +1,89 @@
+package report
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"treco/model"
+)
+
+// Parser parses a report into the suite result of data, results of every report sent for a build are added to the
+// same data
+type Parser interface {
+	Parse(r io.Reader, result *model.Data) error
+}
+
+// Detector can be implemented by a parser, so that its format is detected from the beginning of the report when the
+// report format is not set. Head holds up to the first 64 KB of the report
+type Detector interface {
+	Detect(head []byte) bool
+}
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = make(map[string]Parser)
+)
+
+// Register makes a parser available under the report format name, formats are case insensitive.
+// It panics when parser is nil or a parser is already registered with the name
+func Register(format string, parser Parser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || parser == nil {
+		panic("report: Register parser is nil or has no format name")
+	}
+
+	if _, dup := parsers[format]; dup {
+		panic("report: Register called twice for format " + format)
+	}
+
+	parsers[format] = parser
+}
+
+// Formats returns the sorted names of registered report formats
+func Formats() []string {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	formats := make([]string, 0, len(parsers))
+	for format := range parsers {
+		formats = append(formats, format)
+	}
+
+	sort.Strings(formats)
+	return formats
+}
+
+func registeredParser(format string) (Parser, bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	parser, ok := parsers[strings.ToLower(format)]
+	return parser, ok
+}
+
+// detectRegisteredFormat asks parsers which implement Detector if the report is theirs
+func detectRegisteredFormat(head []byte) string {
+	for _, format := range Formats() {
+		parser, _ := registeredParser(format)
+		if detector, ok := parser.(Detector); ok && detector.Detect(head) {
+			return format
+		}
+	}
+
+	return ""
+}
+
+// isDetectable checks if reports of the format can be detected, by this package or by Detect of its parser
+func isDetectable(format string) bool {
+	if isBuiltInDetectable(format) {
+		return true
+	}
+
+	parser, _ := registeredParser(format)
+	_, ok := parser.(Detector)
+	return ok
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+// inHouseParser reads one test name per line, all of them passed
+type inHouseParser struct{}
+
+func (inHouseParser) Parse(r io.Reader, result *model.Data) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Fields(strings.TrimPrefix(string(b), "#inhouse")) {
+		AddScenarioResult(&result.SuiteResult, model.ScenarioResult{Name: name, Status: PASSED})
+	}
+
+	return nil
+}
+
+func (inHouseParser) Detect(head []byte) bool {
+	return bytes.HasPrefix(head, []byte("#inhouse"))
+}
+
+// withParsers restores the registered parsers once the test ends
+func withParsers(t *testing.T) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	known := make(map[string]Parser, len(parsers))
+	for format, parser := range parsers {
+		known[format] = parser
+	}
+
+	t.Cleanup(func() {
+		parsersMu.Lock()
+		defer parsersMu.Unlock()
+
+		parsers = known
+	})
+}
+
+func TestRegisterParser(t *testing.T) {
+	withParsers(t)
+
+	Register("InHouse", inHouseParser{})
+	require.Contains(t, Formats(), "inhouse")
+	require.Contains(t, Formats(), "junit")
+
+	data := &model.Data{}
+	err := Parse(strings.NewReader("#inhouse\ntest_a\ntest_b"), data)
+	require.NoError(t, err)
+	require.Equal(t, "inhouse", data.SuiteResult.ReportFormat)
+	require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+
+	data = &model.Data{ReportFormat: "inhouse"}
+	err = Parse(strings.NewReader("test_c"), data)
+	require.NoError(t, err)
+	require.Equal(t, "test_c", data.SuiteResult.ScenarioResults[0].Name)
+
+	require.Panics(t, func() { Register("inhouse", inHouseParser{}) })
+	require.Panics(t, func() { Register("other", nil) })
+}
+
+// uncountedParser appends results without counting them in the totals
+type uncountedParser struct{}
+
+func (uncountedParser) Parse(r io.Reader, result *model.Data) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Fields(string(b)) {
+		result.SuiteResult.ScenarioResults = append(result.SuiteResult.ScenarioResults,
+			model.ScenarioResult{Name: name, Status: PASSED})
+	}
+
+	return nil
+}
+
+func TestNormalizeResultsOfRegisteredParser(t *testing.T) {
+	withParsers(t)
+	Register("counted", inHouseParser{})
+	Register("uncounted", uncountedParser{})
+
+	data := &model.Data{ReportFormat: "counted"}
+	err := Parse(strings.NewReader("TestX TestX/case_1 TestX/case_2"), data)
+	require.NoError(t, err)
+	require.Equal(t, uint(2), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+
+	// Totals are not wrapped around by results which were not counted
+	data = &model.Data{ReportFormat: "uncounted"}
+	err = Parse(strings.NewReader("TestX TestX/case_1"), data)
+	require.NoError(t, err)
+	require.Equal(t, uint(0), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(0), data.SuiteResult.TotalPassed)
+}
+
+func TestFormatsAreSorted(t *testing.T) {
+	formats := Formats()
+	for i := 1; i < len(formats); i++ {
+		require.Less(t, formats[i-1], formats[i])
+	}
+}
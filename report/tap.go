@@ -0,0 +1,226 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"treco/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TapDiagnostic struct, fields read from the YAML block following a test point
+type TapDiagnostic struct {
+	DurationMs float64 `yaml:"duration_ms"`
+	Message    string  `yaml:"message"`
+	Severity   string  `yaml:"severity"`
+}
+
+var (
+	errUnableToParseTap = "parsing tap report failed"
+
+	tapTestPoint = regexp.MustCompile(`^(not )?ok\b\s*(\d*)\s*(?:- )?(.*)$`)
+	tapPlan      = regexp.MustCompile(`^(\d+)\.\.(\d+)`)
+)
+
+type tapParser struct{}
+
+func init() {
+	Register("tap", tapParser{})
+}
+
+func (tapParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	valid, bailedOut := false, false
+	plan, points := -1, 0
+
+	// Names of the enclosing subtests by depth, each depth is indented by four spaces
+	subtests := make([]string, 0)
+	// Test points read at depth+1 since the last test point at depth, and whether any of them failed
+	children := make(map[int]int)
+	failedChildren := make(map[int]bool)
+
+	var current *model.ScenarioResult
+	currentDepth, record := 0, false
+	var diagnostic []string
+	inDiagnostic := false
+
+	// add the previous test point once its diagnostic block, if any, has been read
+	flush := func() {
+		if current == nil {
+			return
+		}
+
+		if len(diagnostic) > 0 {
+			d := TapDiagnostic{}
+			if err := yaml.Unmarshal([]byte(strings.Join(diagnostic, "\n")), &d); err != nil {
+				log.Printf("ignoring invalid tap diagnostic for %v: %v\n", current.Name, err)
+			}
+
+			current.TimeTaken = d.DurationMs / 1000
+			current.Message = d.Message
+			current.FailureType = d.Severity
+		}
+
+		if currentDepth == 0 {
+			suiteResult.TimeTaken += current.TimeTaken
+		}
+
+		if record {
+			AddScenarioResult(suiteResult, *current)
+		}
+
+		current = nil
+		diagnostic = nil
+	}
+
+	log.Println("reading tap report")
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		depth := indent / 4
+
+		switch {
+		case inDiagnostic:
+			// YAML block is indented by two spaces more than its test point and ends with "..."
+			if strings.TrimSpace(line) == "..." {
+				inDiagnostic = false
+			} else {
+				diagnostic = append(diagnostic, strings.TrimPrefix(line, strings.Repeat(" ", currentDepth*4+2)))
+			}
+		case current != nil && strings.TrimRight(trimmed, " ") == "---" && indent == currentDepth*4+2:
+			inDiagnostic = true
+		case strings.HasPrefix(trimmed, "TAP version"):
+			valid = true
+		case tapPlan.MatchString(trimmed):
+			valid = true
+			if depth == 0 {
+				m := tapPlan.FindStringSubmatch(trimmed)
+				first, _ := strconv.Atoi(m[1])
+				last, _ := strconv.Atoi(m[2])
+				plan = last - first + 1
+			}
+		case strings.HasPrefix(trimmed, "# Subtest:"):
+			for len(subtests) < depth {
+				subtests = append(subtests, "")
+			}
+			subtests = append(subtests[:depth], strings.TrimSpace(strings.TrimPrefix(trimmed, "# Subtest:")))
+		case strings.HasPrefix(trimmed, "ok"), strings.HasPrefix(trimmed, "not ok"):
+			flush()
+			current = parseTapTestPoint(trimmed)
+			if current == nil {
+				break
+			}
+
+			valid = true
+			currentDepth = depth
+
+			// Test point at a depth ends the subtests nested below it
+			if len(subtests) > depth {
+				subtests = subtests[:depth]
+			}
+			current.Class = strings.Join(subtests, " > ")
+
+			// Test points with subtests are summarised by their subtests, unless failing on their own
+			failed := current.Status == FAILED
+			record = children[depth] == 0 || (failed && !failedChildren[depth])
+			if depth > 0 {
+				children[depth-1]++
+				failedChildren[depth-1] = failedChildren[depth-1] || failed || failedChildren[depth]
+			} else {
+				points++
+			}
+
+			delete(children, depth)
+			delete(failedChildren, depth)
+		case strings.HasPrefix(trimmed, "Bail out!"):
+			// Aborted run is recorded as a failure so remaining tests are not silently missed
+			flush()
+			log.Println("tap run bailed out: " + trimmed)
+			if len(subtests) > depth {
+				subtests = subtests[:depth]
+			}
+			AddScenarioResult(suiteResult, model.ScenarioResult{
+				Name:   strings.TrimSpace(trimmed),
+				Class:  strings.Join(subtests, " > "),
+				Status: FAILED,
+			})
+			valid, bailedOut = true, true
+		}
+
+		if err == io.EOF || bailedOut {
+			break
+		}
+	}
+
+	flush()
+
+	if !valid {
+		return fmt.Errorf(errUnableToParseTap)
+	}
+
+	// Plan tells how many test points to expect, fewer are reported when the run crashed or bailed out
+	if plan >= 0 && plan != points {
+		log.Printf("tap plan of %v tests does not match %v test points\n", plan, points)
+		suiteResult.TotalsMismatch = true
+	}
+
+	return nil
+}
+
+// parseTapTestPoint parses a test point line along with its SKIP or TODO directive
+func parseTapTestPoint(line string) *model.ScenarioResult {
+	matches := tapTestPoint.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+
+	status := PASSED
+	if matches[1] != "" {
+		status = FAILED
+	}
+
+	description, directive := splitTapDirective(matches[3])
+	directive = strings.ToUpper(directive)
+
+	switch {
+	case strings.HasPrefix(directive, "SKIP"):
+		status = SKIPPED
+	case strings.HasPrefix(directive, "TODO") && status == FAILED:
+		// Failing TODO tests are expected failures and do not fail the run
+		status = SKIPPED
+	}
+
+	name := description
+	if name == "" {
+		name = matches[2]
+	}
+
+	return &model.ScenarioResult{
+		Name:   name,
+		Status: status,
+	}
+}
+
+// splitTapDirective splits description on the first unescaped '#'
+func splitTapDirective(s string) (string, string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '#' && (i == 0 || s[i-1] != '\\') {
+			return strings.TrimSpace(strings.ReplaceAll(s[:i], `\#`, "#")), strings.TrimSpace(s[i+1:])
+		}
+	}
+
+	return strings.TrimSpace(strings.ReplaceAll(s, `\#`, "#")), ""
+}
@@ -0,0 +1,152 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidTapContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "tap",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToParseTap), err)
+}
+
+func TestTapReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "tap",
+	}
+
+	contents := `TAP version 14
+1..6
+# Subtest: nested checks
+    1..1
+    not ok 1 - nested failure
+      ---
+      duration_ms: 99
+      ...
+ok 1 - nested checks
+  ---
+  duration_ms: 1500
+  ...
+not ok 2 - config is valid
+  ---
+  message: 'missing key'
+  severity: fail
+  duration_ms: 250
+  ...
+ok 3 - disk is mounted # SKIP not on CI
+not ok 4 - backups rotate # TODO not implemented
+ok 5 - hash \# sign is kept
+ok 6
+`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(6), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(2), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(2), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 1.75, data.SuiteResult.TimeTaken, 0.0001)
+	require.False(t, data.SuiteResult.TotalsMismatch)
+
+	results := data.SuiteResult.ScenarioResults
+	require.Equal(t, "nested failure", results[0].Name)
+	require.Equal(t, "nested checks", results[0].Class)
+	require.Equal(t, FAILED, results[0].Status)
+	require.InDelta(t, 0.099, results[0].TimeTaken, 0.0001)
+	require.Equal(t, "config is valid", results[1].Name)
+	require.Equal(t, "", results[1].Class)
+	require.Equal(t, FAILED, results[1].Status)
+	require.Equal(t, "missing key", results[1].Message)
+	require.Equal(t, "fail", results[1].FailureType)
+	require.Equal(t, SKIPPED, results[2].Status)
+	require.Equal(t, "backups rotate", results[3].Name)
+	require.Equal(t, SKIPPED, results[3].Status)
+	require.Equal(t, "hash # sign is kept", results[4].Name)
+	require.Equal(t, "6", results[5].Name)
+}
+
+func TestTapSubtests(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "tap",
+	}
+
+	contents := `TAP version 14
+# Subtest: test/api.js
+    # Subtest: users
+        ok 1 - creates a user
+        ok 2 - deletes a user
+        1..2
+    ok 1 - users
+    not ok 2 - teardown
+    1..2
+not ok 1 - test/api.js
+# Subtest: test/db.js
+    ok 1 - connects
+    1..1
+not ok 2 - test/db.js
+  ---
+  message: 'process exited with code 1'
+  ...
+1..2
+`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(5), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(3), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(2), data.SuiteResult.TotalFailed)
+	require.False(t, data.SuiteResult.TotalsMismatch)
+
+	results := data.SuiteResult.ScenarioResults
+	require.Equal(t, "creates a user", results[0].Name)
+	require.Equal(t, "test/api.js > users", results[0].Class)
+	require.Equal(t, "teardown", results[2].Name)
+	require.Equal(t, "test/api.js", results[2].Class)
+	require.Equal(t, "connects", results[3].Name)
+	require.Equal(t, "test/db.js", results[3].Class)
+	// Parent failing on its own, while its subtests pass, is kept
+	require.Equal(t, "test/db.js", results[4].Name)
+	require.Equal(t, FAILED, results[4].Status)
+	require.Equal(t, "process exited with code 1", results[4].Message)
+}
+
+func TestTapPlanMismatch(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "tap",
+	}
+
+	contents := `1..10
+ok 1 - first
+ok 2 - second
+not ok 3 - third
+`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(3), data.SuiteResult.TotalExecuted)
+	require.True(t, data.SuiteResult.TotalsMismatch)
+}
+
+func TestTapBailOut(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "tap",
+	}
+
+	contents := `1..3
+ok 1 - first
+Bail out! database unavailable
+ok 2 - never reported
+`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(2), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, "Bail out! database unavailable", data.SuiteResult.ScenarioResults[1].Name)
+	require.True(t, data.SuiteResult.TotalsMismatch)
+}
@@ -0,0 +1,153 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"treco/model"
+)
+
+// TestNGReport struct
+type TestNGReport struct {
+	XMLName xml.Name      `xml:"testng-results"`
+	Suites  []TestNGSuite `xml:"suite"`
+}
+
+// TestNGSuite struct
+type TestNGSuite struct {
+	XMLName xml.Name      `xml:"suite"`
+	Name    string        `xml:"name,attr"`
+	Groups  []TestNGGroup `xml:"groups>group"`
+	Tests   []TestNGTest  `xml:"test"`
+}
+
+// TestNGGroup struct
+type TestNGGroup struct {
+	Name    string              `xml:"name,attr"`
+	Methods []TestNGGroupMethod `xml:"method"`
+}
+
+// TestNGGroupMethod struct
+type TestNGGroupMethod struct {
+	Name  string `xml:"name,attr"`
+	Class string `xml:"class,attr"`
+}
+
+// TestNGTest struct
+type TestNGTest struct {
+	Name    string        `xml:"name,attr"`
+	Classes []TestNGClass `xml:"class"`
+}
+
+// TestNGClass struct
+type TestNGClass struct {
+	Name    string         `xml:"name,attr"`
+	Methods []TestNGMethod `xml:"test-method"`
+}
+
+// TestNGMethod struct
+type TestNGMethod struct {
+	Name       string        `xml:"name,attr"`
+	Status     string        `xml:"status,attr"`
+	DurationMs float64       `xml:"duration-ms,attr"`
+	IsConfig   bool          `xml:"is-config,attr"`
+	Params     []TestNGParam `xml:"params>param"`
+}
+
+// TestNGParam struct
+type TestNGParam struct {
+	Value string `xml:"value"`
+}
+
+var (
+	errUnableToUnmarshalToTestNG = "unmarshalling to testng failed"
+)
+
+type testNGXMLParser struct{}
+
+func init() {
+	Register("testng", testNGXMLParser{})
+}
+
+func (testNGXMLParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	report := TestNGReport{}
+
+	log.Println("unmarshalling to testng report")
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return fmt.Errorf(errUnableToUnmarshalToTestNG)
+	}
+
+	for _, suite := range report.Suites {
+		groups := testNGGroupsByMethod(suite.Groups)
+
+		for _, test := range suite.Tests {
+			for _, class := range test.Classes {
+				for _, method := range class.Methods {
+					// Skip @Before/@After configuration methods
+					if method.IsConfig {
+						continue
+					}
+
+					status := PASSED
+					switch strings.ToUpper(method.Status) {
+					case "FAIL":
+						status = FAILED
+					case "SKIP":
+						status = SKIPPED
+					}
+
+					timeTaken := method.DurationMs / 1000
+					suiteResult.TimeTaken += timeTaken
+
+					// Parameters are appended as name[params], which normalization maps back to the scenario of the method
+					name := method.Name
+					params := testNGParamValues(method.Params)
+					if params != "" {
+						name = fmt.Sprintf("%s[%s]", name, params)
+					}
+
+					methodGroups := groups[class.Name+"."+method.Name]
+
+					AddScenarioResult(suiteResult, model.ScenarioResult{
+						Name:       name,
+						Class:      class.Name,
+						Status:     status,
+						TimeTaken:  timeTaken,
+						Features:   methodGroups,
+						Tags:       methodGroups,
+						Parameters: params,
+					})
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// testNGGroupsByMethod maps fully qualified method names to the groups they belong to
+func testNGGroupsByMethod(groups []TestNGGroup) map[string][]string {
+	methodGroups := make(map[string][]string)
+	for _, group := range groups {
+		for _, method := range group.Methods {
+			key := method.Class + "." + method.Name
+			methodGroups[key] = append(methodGroups[key], group.Name)
+		}
+	}
+
+	return methodGroups
+}
+
+// testNGParamValues joins parameter values as listed in the report
+func testNGParamValues(params []TestNGParam) string {
+	values := make([]string, 0, len(params))
+	for _, param := range params {
+		values = append(values, strings.TrimSpace(param.Value))
+	}
+
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidTestNGContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "testng",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToTestNG), err)
+}
+
+func TestTestNGReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "testng",
+	}
+
+	contents := `
+	<?xml version="1.0" encoding="UTF-8"?>
+	<testng-results ignored="0" total="4" passed="2" failed="1" skipped="1">
+		<reporter-output/>
+		<suite name="Suite" duration-ms="4500">
+			<groups>
+				<group name="smoke">
+					<method signature="LoginTest.testLogin()" name="testLogin" class="com.app.LoginTest"/>
+				</group>
+				<group name="PROJ-42">
+					<method signature="LoginTest.testLogin()" name="testLogin" class="com.app.LoginTest"/>
+				</group>
+			</groups>
+			<test name="Regression" duration-ms="4500">
+				<class name="com.app.LoginTest">
+					<test-method status="PASS" signature="setUp()" name="setUp" is-config="true" duration-ms="100"/>
+					<test-method status="PASS" signature="testLogin()" name="testLogin" duration-ms="1500">
+						<params>
+							<param index="0"><value><![CDATA[chrome]]></value></param>
+							<param index="1"><value><![CDATA[1]]></value></param>
+						</params>
+					</test-method>
+					<test-method status="FAIL" signature="testLogout()" name="testLogout" duration-ms="2000">
+						<exception class="java.lang.AssertionError"><message><![CDATA[expected true]]></message></exception>
+					</test-method>
+					<test-method status="SKIP" signature="testReset()" name="testReset" duration-ms="0"/>
+					<test-method status="PASS" signature="testHome()" name="testHome" duration-ms="1000"/>
+				</class>
+			</test>
+		</suite>
+	</testng-results>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(4), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(2), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 4.5, data.SuiteResult.TimeTaken, 0.0001)
+	require.Equal(t, 4, len(data.SuiteResult.ScenarioResults))
+
+	login := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "testLogin", login.Name)
+	require.Equal(t, "com.app.LoginTest", login.Class)
+	require.Equal(t, "chrome, 1", login.Parameters)
+	require.Equal(t, []string{"smoke", "PROJ-42"}, login.Tags)
+	require.Equal(t, []string{"smoke", "PROJ-42"}, login.Features)
+
+	require.Equal(t, FAILED, data.SuiteResult.ScenarioResults[1].Status)
+	require.Equal(t, SKIPPED, data.SuiteResult.ScenarioResults[2].Status)
+	require.Empty(t, data.SuiteResult.ScenarioResults[3].Tags)
+}
@@ -0,0 +1,125 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"treco/model"
+)
+
+// TrxTestRun struct
+type TrxTestRun struct {
+	XMLName         xml.Name            `xml:"TestRun"`
+	Results         []TrxUnitTestResult `xml:"Results>UnitTestResult"`
+	TestDefinitions []TrxUnitTest       `xml:"TestDefinitions>UnitTest"`
+}
+
+// TrxUnitTestResult struct
+type TrxUnitTestResult struct {
+	TestID   string `xml:"testId,attr"`
+	TestName string `xml:"testName,attr"`
+	Duration string `xml:"duration,attr"`
+	Outcome  string `xml:"outcome,attr"`
+}
+
+// TrxUnitTest struct
+type TrxUnitTest struct {
+	ID         string        `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Categories []TrxCategory `xml:"TestCategory>TestCategoryItem"`
+	TestMethod TrxTestMethod `xml:"TestMethod"`
+}
+
+// TrxCategory struct
+type TrxCategory struct {
+	Name string `xml:"TestCategory,attr"`
+}
+
+// TrxTestMethod struct
+type TrxTestMethod struct {
+	ClassName string `xml:"className,attr"`
+	Name      string `xml:"name,attr"`
+}
+
+var (
+	errUnableToUnmarshalToTrx = "unmarshalling to trx failed"
+)
+
+type trxXMLParser struct{}
+
+func init() {
+	Register("trx", trxXMLParser{})
+}
+
+func (trxXMLParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	run := TrxTestRun{}
+
+	log.Println("unmarshalling to trx report")
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return fmt.Errorf(errUnableToUnmarshalToTrx)
+	}
+
+	definitions := make(map[string]TrxUnitTest, len(run.TestDefinitions))
+	for _, definition := range run.TestDefinitions {
+		definitions[definition.ID] = definition
+	}
+
+	for _, tr := range run.Results {
+		status := PASSED
+		switch strings.ToLower(tr.Outcome) {
+		case "failed", "error", "timeout", "aborted":
+			status = FAILED
+		case "notexecuted", "inconclusive", "pending", "notrunnable":
+			status = SKIPPED
+		}
+
+		timeTaken := trxDuration(tr.Duration)
+		suiteResult.TimeTaken += timeTaken
+
+		definition := definitions[tr.TestID]
+
+		categories := make([]string, 0, len(definition.Categories))
+		for _, category := range definition.Categories {
+			categories = append(categories, category.Name)
+		}
+
+		// Class name is assembly qualified, i.e. "Namespace.Class, Assembly, Version=..."
+		class := strings.TrimSpace(strings.Split(definition.TestMethod.ClassName, ",")[0])
+
+		AddScenarioResult(suiteResult, model.ScenarioResult{
+			Name:      tr.TestName,
+			Class:     class,
+			Status:    status,
+			TimeTaken: timeTaken,
+			Features:  categories,
+			Tags:      categories,
+		})
+	}
+
+	return nil
+}
+
+// trxDuration converts duration in hh:mm:ss.fffffff format to seconds
+func trxDuration(duration string) float64 {
+	parts := strings.Split(duration, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+
+	var seconds float64
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+
+		seconds = seconds*60 + v
+	}
+
+	return seconds
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidTrxContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "trx",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToTrx), err)
+}
+
+func TestTrxReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "trx",
+	}
+
+	contents := `<?xml version="1.0" encoding="utf-8"?>
+	<TestRun id="1" name="build" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+		<Results>
+			<UnitTestResult executionId="e1" testId="t1" testName="ValidLogin" duration="00:00:01.5000000" outcome="Passed" />
+			<UnitTestResult executionId="e2" testId="t2" testName="InvalidLogin" duration="00:01:00.2500000" outcome="Failed">
+				<Output><ErrorInfo><Message>Assert.AreEqual failed</Message></ErrorInfo></Output>
+			</UnitTestResult>
+			<UnitTestResult executionId="e3" testId="t3" testName="Logout" duration="00:00:00" outcome="NotExecuted" />
+		</Results>
+		<TestDefinitions>
+			<UnitTest name="ValidLogin" id="t1">
+				<TestCategory>
+					<TestCategoryItem TestCategory="smoke" />
+					<TestCategoryItem TestCategory="PROJ-5" />
+				</TestCategory>
+				<TestMethod className="App.LoginTests, App.Tests, Version=1.0.0.0" name="ValidLogin" />
+			</UnitTest>
+			<UnitTest name="InvalidLogin" id="t2">
+				<TestMethod className="App.LoginTests" name="InvalidLogin" />
+			</UnitTest>
+			<UnitTest name="Logout" id="t3">
+				<TestMethod className="App.LoginTests" name="Logout" />
+			</UnitTest>
+		</TestDefinitions>
+	</TestRun>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(3), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 61.75, data.SuiteResult.TimeTaken, 0.0001)
+
+	validLogin := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, "ValidLogin", validLogin.Name)
+	require.Equal(t, "App.LoginTests", validLogin.Class)
+	require.InDelta(t, 1.5, validLogin.TimeTaken, 0.0001)
+	require.Equal(t, []string{"smoke", "PROJ-5"}, validLogin.Tags)
+
+	require.Equal(t, FAILED, data.SuiteResult.ScenarioResults[1].Status)
+	require.Equal(t, SKIPPED, data.SuiteResult.ScenarioResults[2].Status)
+}
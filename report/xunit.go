@@ -0,0 +1,104 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"treco/model"
+)
+
+// XUnitAssemblies struct
+type XUnitAssemblies struct {
+	XMLName    xml.Name        `xml:"assemblies"`
+	Assemblies []XUnitAssembly `xml:"assembly"`
+}
+
+// XUnitAssembly struct
+type XUnitAssembly struct {
+	Time        float64           `xml:"time,attr"`
+	Collections []XUnitCollection `xml:"collection"`
+}
+
+// XUnitCollection struct
+type XUnitCollection struct {
+	Tests []XUnitTest `xml:"test"`
+}
+
+// XUnitTest struct
+type XUnitTest struct {
+	Name   string       `xml:"name,attr"`
+	Type   string       `xml:"type,attr"`
+	Result string       `xml:"result,attr"`
+	Time   float64      `xml:"time,attr"`
+	Traits []XUnitTrait `xml:"traits>trait"`
+}
+
+// XUnitTrait struct
+type XUnitTrait struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+var (
+	errUnableToUnmarshalToXUnit = "unmarshalling to xunit failed"
+)
+
+type xUnitXMLParser struct{}
+
+func init() {
+	Register("xunit", xUnitXMLParser{})
+}
+
+func (xUnitXMLParser) Parse(r io.Reader, result *model.Data) error {
+	suiteResult := &result.SuiteResult
+
+	report := XUnitAssemblies{}
+
+	log.Println("unmarshalling to xunit report")
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return fmt.Errorf(errUnableToUnmarshalToXUnit)
+	}
+
+	for _, assembly := range report.Assemblies {
+		suiteResult.TimeTaken += assembly.Time
+
+		for _, collection := range assembly.Collections {
+			for _, test := range collection.Tests {
+				status := PASSED
+				switch strings.ToLower(test.Result) {
+				case "fail":
+					status = FAILED
+				case "skip", "notrun":
+					status = SKIPPED
+				}
+
+				// Test names are usually prefixed with the fully qualified class name
+				name := strings.TrimPrefix(test.Name, test.Type+".")
+
+				features := make([]string, 0, len(test.Traits))
+				tags := make([]string, 0, len(test.Traits))
+				for _, trait := range test.Traits {
+					features = append(features, trait.Value)
+					if strings.EqualFold(trait.Name, "Category") {
+						tags = append(tags, trait.Value)
+					} else {
+						tags = append(tags, trait.Name+"="+trait.Value)
+					}
+				}
+
+				AddScenarioResult(suiteResult, model.ScenarioResult{
+					Name:      name,
+					Class:     test.Type,
+					Status:    status,
+					TimeTaken: test.Time,
+					Features:  features,
+					Tags:      tags,
+				})
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"treco/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidXUnitContent(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "xunit",
+	}
+
+	contents := "test"
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.Equal(t, fmt.Errorf(errUnableToUnmarshalToXUnit), err)
+}
+
+func TestXUnitReportParsing(t *testing.T) {
+	data := &model.Data{
+		ReportFormat: "xunit",
+	}
+
+	contents := `
+	<?xml version="1.0" encoding="utf-8"?>
+	<assemblies>
+		<assembly name="App.Tests.dll" total="3" passed="1" failed="1" skipped="1" time="1.75">
+			<collection name="Test collection for App.LoginTests" total="3" time="1.5">
+				<test name="App.LoginTests.ValidLogin(browser: &quot;chrome&quot;)" type="App.LoginTests" method="ValidLogin" time="1" result="Pass">
+					<traits>
+						<trait name="Category" value="smoke" />
+						<trait name="Jira" value="PROJ-9" />
+					</traits>
+				</test>
+				<test name="App.LoginTests.InvalidLogin" type="App.LoginTests" method="InvalidLogin" time="0.5" result="Fail">
+					<failure exception-type="Xunit.Sdk.EqualException">
+						<message><![CDATA[Assert.Equal() Failure]]></message>
+					</failure>
+				</test>
+				<test name="App.LoginTests.Logout" type="App.LoginTests" method="Logout" time="0" result="Skip">
+					<reason><![CDATA[not ready]]></reason>
+				</test>
+			</collection>
+		</assembly>
+	</assemblies>
+	`
+	err := Parse(bytes.NewReader([]byte(contents)), data)
+	require.NoError(t, err, "Parsing error")
+	require.Equal(t, uint(3), data.SuiteResult.TotalExecuted)
+	require.Equal(t, uint(1), data.SuiteResult.TotalPassed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalFailed)
+	require.Equal(t, uint(1), data.SuiteResult.TotalSkipped)
+	require.InDelta(t, 1.75, data.SuiteResult.TimeTaken, 0.0001)
+
+	validLogin := data.SuiteResult.ScenarioResults[0]
+	require.Equal(t, `ValidLogin(browser: "chrome")`, validLogin.Name)
+	require.Equal(t, "App.LoginTests", validLogin.Class)
+	require.Equal(t, []string{"smoke", "Jira=PROJ-9"}, validLogin.Tags)
+	require.Equal(t, []string{"smoke", "PROJ-9"}, validLogin.Features)
+
+	require.Equal(t, FAILED, data.SuiteResult.ScenarioResults[1].Status)
+	require.Equal(t, SKIPPED, data.SuiteResult.ScenarioResults[2].Status)
+}
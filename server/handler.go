@@ -2,9 +2,11 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
@@ -23,20 +25,23 @@ const (
 	Service      = "SERVICE_NAME"
 	TestType     = "TEST_TYPE"
 	Coverage     = "COVERAGE"
+	CoverageFile = "COVERAGE_FILE"
 
 	expectedContentType = "multipart/form-data"
+
+	// Request body beyond this size is stored in temporary files
+	maxMemory = 32 << 20
 )
 
 var (
-	validTestTypes     = [...]string{"unit", "contract", "integration", "e2e"}
-	validReportFormats = [...]string{"junit"}
+	validTestTypes = [...]string{"unit", "contract", "integration", "e2e", "performance"}
 
 	errInvalidTestType       = "test type %v is invalid, should be one of %v"
 	errInvalidReportFormats  = "report format %v is invalid, should be one of %v"
 	errCoverageValueNotFloat = "coverage value should be a floating number"
 
 	// RequiredParams ...
-	RequiredParams = [...]string{BuildID, Environment, Jira, ReportFormat, Service, TestType}
+	RequiredParams = [...]string{BuildID, Environment, Jira, Service, TestType}
 )
 
 // Error struct having code and description
@@ -45,24 +50,60 @@ type Error struct {
 	Description string
 }
 
+// invalidReportError is returned by Process when a report can not be parsed, which is an error of the request
+type invalidReportError struct {
+	err error
+}
+
+func (e invalidReportError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidReportError) Unwrap() error {
+	return e.err
+}
+
 // PublishHandler ...
 type PublishHandler struct {
 }
 
 // ServerHTTP ...
 func (p PublishHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Decompress request body
+	if encoding := r.Header.Get("content-encoding"); encoding != "" {
+		body, err := report.Decompress(r.Body, encoding)
+		if err != nil {
+			sendErrorResponse(w, err, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		r.Body = reportFile{Reader: body, Closer: r.Body}
+		r.Header.Del("content-encoding")
+	}
+
 	// Validate request
 	if status, err := validatePublishRequest(r); err != nil {
 		sendErrorResponse(w, err, err.Error(), status)
 		return
 	}
 
-	rf, err := readFileFromRequest(r)
+	files, err := readFilesFromRequest(r, ReportFile)
 	if err != nil {
 		sendErrorResponse(w, err, "unable to retrieve report file", http.StatusBadRequest)
 		return
 	}
 
+	defer closeFiles(files)
+
+	// Coverage file is optional
+	coverageFiles, err := readFilesFromRequest(r, CoverageFile)
+	if err != nil && err != http.ErrMissingFile {
+		sendErrorResponse(w, err, "unable to retrieve coverage file", http.StatusBadRequest)
+		return
+	}
+
+	defer closeFiles(coverageFiles)
+
 	cfg := conf.Config{
 		Build:        r.FormValue(strings.ToLower(BuildID)),
 		Environment:  r.FormValue(strings.ToLower(Environment)),
@@ -73,9 +114,27 @@ func (p PublishHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Coverage:     r.FormValue(strings.ToLower(Coverage)),
 	}
 
-	// Process file
-	if err := Process(cfg, rf); err != nil {
+	// Process files
+	reports := make([]io.Reader, 0, len(files))
+	for _, f := range files {
+		reports = append(reports, f)
+	}
+
+	coverageReports := make([]io.Reader, 0, len(coverageFiles))
+	for _, f := range coverageFiles {
+		coverageReports = append(coverageReports, f)
+	}
+
+	if err := Process(cfg, reports, coverageReports...); err != nil {
 		log.Println("error processing: " + err.Error())
+
+		// Reports which can not be parsed are rejected with the reason, e.g. the format the report looks like
+		var invalidReport invalidReportError
+		if errors.As(err, &invalidReport) {
+			sendErrorResponse(w, err, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		sendErrorResponse(w, err, "unable to process the request", http.StatusInternalServerError)
 		return
 	}
@@ -84,18 +143,61 @@ func (p PublishHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-// Read the report file from request
-func readFileFromRequest(r *http.Request) (io.Reader, error) {
-	reportFile, _, err := r.FormFile(strings.ToLower(ReportFile))
+// reportFile is a report read from request, which is decompressed when sent compressed
+type reportFile struct {
+	io.Reader
+	io.Closer
+}
+
+// Read the files of a form field from request, file part can be sent multiple times.
+// Compressed files are detected by content-encoding of the part or their contents
+func readFilesFromRequest(r *http.Request, field string) ([]io.ReadCloser, error) {
+	if r.MultipartForm == nil {
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			return nil, err
+		}
+	}
+
+	headers := r.MultipartForm.File[strings.ToLower(field)]
+	if len(headers) == 0 {
+		return nil, http.ErrMissingFile
+	}
+
+	files := make([]io.ReadCloser, 0, len(headers))
+	for _, header := range headers {
+		f, err := openFile(header)
+		if err != nil {
+			closeFiles(files)
+			return nil, err
+		}
+
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+func closeFiles(files []io.ReadCloser) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
+
+// Open the file part, decompressing it when sent with a content-encoding
+func openFile(header *multipart.FileHeader) (io.ReadCloser, error) {
+	f, err := header.Open()
 	if err != nil {
 		return nil, err
 	}
 
-	defer func() {
-		_ = reportFile.Close()
-	}()
+	// Compressed files without content-encoding are detected from their contents when parsed
+	rf, err := report.Decompress(f, header.Header.Get("content-encoding"))
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
 
-	return reportFile, nil
+	return reportFile{Reader: rf, Closer: f}, nil
 }
 
 // Validate incoming publish request
@@ -123,10 +225,17 @@ func validatePublishRequest(r *http.Request) (int, error) {
 		return http.StatusBadRequest, fmt.Errorf("missing params: %v", strings.Join(missingParams, ", "))
 	}
 
+	// Coverage is computed from coverage file when sent
+	coverage := r.FormValue(strings.ToLower(Coverage))
+	hasCoverageFile := r.MultipartForm != nil && len(r.MultipartForm.File[strings.ToLower(CoverageFile)]) > 0
+	if coverage == "" && !hasCoverageFile {
+		return http.StatusBadRequest, fmt.Errorf("missing params: %v or %v", strings.ToLower(Coverage),
+			strings.ToLower(CoverageFile))
+	}
+
 	// Validate param values
 	testType := r.FormValue(strings.ToLower(TestType))
 	reportFormat := r.FormValue(strings.ToLower(ReportFormat))
-	coverage := r.FormValue(strings.ToLower(Coverage))
 	if err := ValidateParams(testType, reportFormat, coverage); err != nil {
 		return http.StatusBadRequest, err
 	}
@@ -148,8 +257,9 @@ func sendErrorResponse(w http.ResponseWriter, err error, description string, cod
 	_, _ = w.Write(b)
 }
 
-// proces the request
-func Process(cfg conf.Config, f io.Reader) error {
+// Process parses the report files into a single suite result and saves it, coverage is taken from coverage reports
+// when sent
+func Process(cfg conf.Config, files []io.Reader, coverageReports ...io.Reader) error {
 	var err error
 	coverage, _ := strconv.ParseFloat(cfg.Coverage, 64)
 
@@ -167,9 +277,18 @@ func Process(cfg conf.Config, f io.Reader) error {
 	}
 
 	// Transform file data into required format
-	err = report.Parse(f, data)
-	if err != nil {
-		return err
+	for _, f := range files {
+		err = report.Parse(f, data)
+		if err != nil {
+			return invalidReportError{err: err}
+		}
+	}
+
+	if len(coverageReports) > 0 {
+		err = report.ParseCoverage(data, coverageReports...)
+		if err != nil {
+			return invalidReportError{err: err}
+		}
 	}
 
 	// Write to storage
@@ -189,13 +308,13 @@ func ValidateParams(testType, reportType, coverage string) error {
 		return fmt.Errorf(errInvalidTestType, testType, validTestTypes)
 	}
 
-	//check for valid test report format
-	if !isValid(reportType, validReportFormats[:]) {
-		return fmt.Errorf(errInvalidReportFormats, reportType, validReportFormats)
+	//check for valid test report format, format is detected from report when not set
+	if reportFormats := report.Formats(); reportType != "" && !isValid(reportType, reportFormats) {
+		return fmt.Errorf(errInvalidReportFormats, reportType, reportFormats)
 	}
 
-	//check coverage is in float
-	if _, err := strconv.ParseFloat(coverage, 64); err != nil {
+	//check coverage is in float, coverage is not set when computed from coverage file
+	if _, err := strconv.ParseFloat(coverage, 64); coverage != "" && err != nil {
 		return fmt.Errorf(errCoverageValueNotFloat)
 	}
 
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"treco/report"
 
 	"github.com/stretchr/testify/require"
 )
@@ -74,7 +76,7 @@ func TestValidateParamsWithInvalidParams(t *testing.T) {
 			testType:   "unit",
 			reportType: "mbunit",
 			coverage:   "20.10",
-			err:        fmt.Errorf(errInvalidReportFormats, "mbunit", validReportFormats),
+			err:        fmt.Errorf(errInvalidReportFormats, "mbunit", report.Formats()),
 		},
 		{
 			testName:   "invalid coverage",
@@ -96,7 +98,7 @@ func TestValidateParamsWithInvalidParams(t *testing.T) {
 
 func TestValidateParamsWithValidValues(t *testing.T) {
 	for _, testType := range validTestTypes {
-		err := ValidateParams(testType, validReportFormats[0], "0.0")
+		err := ValidateParams(testType, report.Formats()[0], "0.0")
 		require.NoError(t, err)
 	}
 }
@@ -203,8 +205,22 @@ func TestPublishHandlerWithInvalidData(t *testing.T) {
 				testName: "invalid file contents",
 				request:  request,
 				resErr: Error{
-					Code:        http.StatusInternalServerError,
-					Description: "unable to process the request",
+					Code:        http.StatusBadRequest,
+					Description: "unmarshalling to junit failed",
+				},
+			}
+		},
+		func() testData {
+			request, err := createTestHTTPRequest(MethodPost, ContentTypeMultipartFormData,
+				testRequestParams, `[{"uri": "login.feature", "elements": []}]`)
+			require.NoError(t, err)
+
+			return testData{
+				testName: "report of another format",
+				request:  request,
+				resErr: Error{
+					Code:        http.StatusBadRequest,
+					Description: "unmarshalling to junit failed, report looks like cucumber instead of junit",
 				},
 			}
 		},
@@ -258,7 +274,153 @@ func TestPublishHandlerWithValidRequest(t *testing.T) {
 	require.Equal(t, http.StatusOK, res.Code)
 }
 
-func createTestHTTPRequest(method, contentType string, params map[string]string, fileContents string) (*http.Request, error) {
+func TestPublishHandlerWithMultipleReportFiles(t *testing.T) {
+	req, err := createTestHTTPRequest(MethodPost, ContentTypeMultipartFormData, testRequestParams, testFileContent, testFileContent)
+	require.NoError(t, err)
+
+	files, err := readFilesFromRequest(req, ReportFile)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(files))
+
+	res := httptest.NewRecorder()
+	publishHandler := PublishHandler{}
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestPublishHandlerWithoutReportFile(t *testing.T) {
+	req, err := createTestHTTPRequest(MethodPost, ContentTypeMultipartFormData, testRequestParams)
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	publishHandler := PublishHandler{}
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestPublishHandlerWithoutReportFormat(t *testing.T) {
+	requestParams := make(map[string]string)
+	for k, v := range testRequestParams {
+		requestParams[k] = v
+	}
+
+	delete(requestParams, strings.ToLower(ReportFormat))
+	req, err := createTestHTTPRequest(MethodPost, ContentTypeMultipartFormData, requestParams, testFileContent)
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	publishHandler := PublishHandler{}
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestPublishHandlerWithCoverageFile(t *testing.T) {
+	requestParams := make(map[string]string)
+	for k, v := range testRequestParams {
+		requestParams[k] = v
+	}
+
+	delete(requestParams, strings.ToLower(Coverage))
+	req, err := createTestHTTPRequest(MethodPost, ContentTypeMultipartFormData, requestParams, testFileContent)
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	publishHandler := PublishHandler{}
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusBadRequest, res.Code)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for k, v := range requestParams {
+		require.NoError(t, writer.WriteField(k, v))
+	}
+
+	part, err := writer.CreateFormFile(strings.ToLower(ReportFile), "some_file.xml")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(testFileContent))
+	require.NoError(t, err)
+
+	part, err = writer.CreateFormFile(strings.ToLower(CoverageFile), "coverage.out")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("mode: set\ntreco/server/handler.go:57.75,59.2 2 1\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req = httptest.NewRequest(MethodPost, "/v1/publish/report", body)
+	req.Header.Set(ContentTypeHeader, writer.FormDataContentType())
+
+	res = httptest.NewRecorder()
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestPublishHandlerWithCompressedReportFile(t *testing.T) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for k, v := range testRequestParams {
+		require.NoError(t, writer.WriteField(k, v))
+	}
+
+	part, err := writer.CreateFormFile(strings.ToLower(ReportFile), "some_file.xml.gz")
+	require.NoError(t, err)
+
+	gw := gzip.NewWriter(part)
+	_, err = gw.Write([]byte(testFileContent))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(MethodPost, "/v1/publish/report", body)
+	req.Header.Set(ContentTypeHeader, writer.FormDataContentType())
+
+	res := httptest.NewRecorder()
+	publishHandler := PublishHandler{}
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestPublishHandlerWithPlainReportNamedAsCompressed(t *testing.T) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for k, v := range testRequestParams {
+		require.NoError(t, writer.WriteField(k, v))
+	}
+
+	part, err := writer.CreateFormFile(strings.ToLower(ReportFile), "some_file.xml.gz")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(testFileContent))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(MethodPost, "/v1/publish/report", body)
+	req.Header.Set(ContentTypeHeader, writer.FormDataContentType())
+
+	res := httptest.NewRecorder()
+	publishHandler := PublishHandler{}
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestPublishHandlerWithUnsupportedContentEncoding(t *testing.T) {
+	req, err := createTestHTTPRequest(MethodPost, ContentTypeMultipartFormData, testRequestParams, testFileContent)
+	require.NoError(t, err)
+	req.Header.Set("content-encoding", "br")
+
+	res := httptest.NewRecorder()
+	publishHandler := PublishHandler{}
+	publishHandler.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func createTestHTTPRequest(method, contentType string, params map[string]string, fileContents ...string) (*http.Request, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
@@ -270,15 +432,17 @@ func createTestHTTPRequest(method, contentType string, params map[string]string,
 		}
 	}
 
-	//Add file to multipart data
-	part, _ := writer.CreateFormFile(strings.ToLower(ReportFile), "some_file.xml")
-	_, err := io.Copy(part, bytes.NewReader([]byte(fileContents)))
-	if err != nil {
-		return &http.Request{}, err
+	//Add files to multipart data
+	for _, contents := range fileContents {
+		part, _ := writer.CreateFormFile(strings.ToLower(ReportFile), "some_file.xml")
+		_, err := io.Copy(part, bytes.NewReader([]byte(contents)))
+		if err != nil {
+			return &http.Request{}, err
+		}
 	}
 
 	//Close writer
-	err = writer.Close()
+	err := writer.Close()
 	if err != nil {
 		return &http.Request{}, err
 	}
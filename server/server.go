@@ -6,14 +6,13 @@ import (
 	"log"
 	"net/http"
 	"treco/conf"
-	"treco/model"
+	"treco/migration"
 	"treco/storage"
 )
 
-var DBEntities = []interface{}{&model.SuiteResult{}, &model.ScenarioResult{}, &model.Scenario{}, &model.Feature{}}
-
-// Starts the server mode
-func Start(cfgFile string, port int) {
+// Starts the server mode, pending migrations are applied unless skipped. Server refuses to start when migrations are
+// skipped and the schema is behind
+func Start(cfgFile string, port int, skipMigrations bool) {
 	var err error
 
 	// check config file
@@ -38,7 +37,12 @@ func Start(cfgFile string, port int) {
 	}()
 
 	//DB setup
-	err = (*handler).Setup(DBEntities...)
+	if skipMigrations {
+		err = migration.Check((*handler).GetDB())
+	} else {
+		err = migration.Up((*handler).GetDB())
+	}
+
 	if err != nil {
 		log.Fatal(err)
 	}
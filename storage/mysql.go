@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// MySQLIndexedStringSize is the size in characters of indexed string columns on MySQL, the largest which fits four
+// utf8mb4 columns in an InnoDB index
+const MySQLIndexedStringSize = 191
+
+// MySQL DB, MariaDB is supported as well
+type MySQL struct {
+	db *gorm.DB
+}
+
+// GetDB returns DB instance
+func (m MySQL) GetDB() *gorm.DB {
+	return m.db
+}
+
+// Insert model into DB
+func (m MySQL) Insert(model interface{}) error {
+	return m.db.Create(model).Error
+}
+
+// Close DB connection
+func (m MySQL) Close() error {
+	db, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.Close()
+}
+
+var connectToMySQLDB = func(dsn string) (*gorm.DB, error) {
+	return gorm.Open(mysqlDialector{Dialector: mysql.Open(dsn).(*mysql.Dialector)}, &gorm.Config{})
+}
+
+func newMySQLDB(s db) (MySQL, error) {
+	log.Println("connecting to MySQL")
+
+	dsn := fmt.Sprintf("%v:%v@tcp(%v:%v)/%v?charset=utf8mb4&parseTime=true", s.User, s.Password, s.Host, s.Port, s.Name)
+	db, err := connectToMySQLDB(dsn)
+	if err != nil {
+		return MySQL{}, err
+	}
+
+	return MySQL{db: db}, nil
+}
+
+// mysqlDialector creates string columns of unique indexes and primary keys as varchar, MySQL cannot index the text
+// columns which are created for strings without a size
+type mysqlDialector struct {
+	*mysql.Dialector
+}
+
+// DataTypeOf returns type of the column. Columns of unique indexes and primary keys, including the columns of join
+// tables referencing them, are compared byte by byte, the default collation treats values which differ only in case or
+// accents as the same value
+func (d mysqlDialector) DataTypeOf(field *schema.Field) string {
+	if field.DataType == schema.String && (field.PrimaryKey || field.TagSettings["UNIQUEINDEX"] != "") {
+		dataType := d.Dialector.DataTypeOf(field)
+		if field.Size == 0 {
+			dataType = fmt.Sprintf("varchar(%d)", MySQLIndexedStringSize)
+		}
+
+		return dataType + " COLLATE utf8mb4_bin"
+	}
+
+	return d.Dialector.DataTypeOf(field)
+}
+
+// Migrator uses the dialector for column types
+func (d mysqlDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	m := d.Dialector.Migrator(db).(mysql.Migrator)
+	m.Migrator.Dialector = d
+	return m
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// SQLite DB stored in a local file
+type SQLite struct {
+	db *gorm.DB
+}
+
+// GetDB returns DB instance
+func (s SQLite) GetDB() *gorm.DB {
+	return s.db
+}
+
+// Insert model into DB
+func (s SQLite) Insert(model interface{}) error {
+	return s.db.Create(model).Error
+}
+
+// Close DB connection
+func (s SQLite) Close() error {
+	db, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.Close()
+}
+
+// newSQLiteDB opens the DB file at the path set in DB_NAME, the file is created when it does not exist.
+// The SQLite driver needs cgo, see sqlite_cgo.go
+func newSQLiteDB(s db) (SQLite, error) {
+	log.Println("connecting to SQLite")
+
+	// Wait on concurrent writes instead of failing with database is locked
+	dsn := s.Name + "?_busy_timeout=5000&_foreign_keys=on"
+	db, err := connectToSQLiteDB(dsn)
+	if err != nil {
+		return SQLite{}, err
+	}
+
+	return SQLite{db: db}, nil
+}
@@ -0,0 +1,12 @@
+//go:build cgo
+
+package storage
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var connectToSQLiteDB = func(dsn string) (*gorm.DB, error) {
+	return gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+}
@@ -0,0 +1,16 @@
+//go:build !cgo
+
+package storage
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+var errSQLiteNeedsCgo = fmt.Errorf("sqlite is not supported by this build of treco, please build it with CGO_ENABLED=1")
+
+// SQLite driver is a cgo binding of the SQLite library, builds without cgo fail on connect instead of on compile
+var connectToSQLiteDB = func(dsn string) (*gorm.DB, error) {
+	return nil, errSQLiteNeedsCgo
+}
@@ -0,0 +1,40 @@
+//go:build cgo
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"treco/conf"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidSQLiteDBType(t *testing.T) {
+	// Other db details are not needed
+	for _, dbParam := range dbParams {
+		conf.Set(dbParam, "")
+		_ = os.Unsetenv(dbParam)
+	}
+
+	conf.Set(DBType, "sqlite")
+	conf.Set(DBName, filepath.Join(t.TempDir(), "treco.db"))
+	t.Cleanup(func() {
+		conf.Set(DBType, "")
+		conf.Set(DBName, "")
+	})
+
+	err := New()
+	require.NoError(t, err)
+	require.IsType(t, SQLite{}, dbHandler)
+
+	type entity struct {
+		ID   uint `gorm:"primarykey"`
+		Name string
+	}
+
+	require.NoError(t, dbHandler.GetDB().AutoMigrate(&entity{}))
+	require.NoError(t, dbHandler.Insert(&entity{Name: "test"}))
+	require.NoError(t, dbHandler.Close())
+}
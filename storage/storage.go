@@ -8,6 +8,8 @@ import (
 	"log"
 	"strings"
 	"treco/conf"
+
+	"gorm.io/gorm"
 )
 
 // DB Details
@@ -26,7 +28,7 @@ var dbHandler DBHandler
 type DBHandler interface {
 	Insert(model interface{}) error
 	Close() error
-	Setup(entities ...interface{}) error
+	GetDB() *gorm.DB
 }
 
 type db struct {
@@ -47,13 +49,21 @@ var (
 	errMissingDBParams = fmt.Errorf("missing db details, please set below environment variables: "+
 		"%v, %v, %v, %v, %v, %v", DBType, DBName, DBHost, DBPort, DBUser, DBPassword)
 
+	errMissingSQLiteParams = fmt.Errorf("missing db details, please set %v to the path of the SQLite DB file", DBName)
+
 	errStrInvalidStorageType = "storage type %v not supported, please check value of DB_TYPE in environment variables"
 )
 
 // New initiates a new DB connection
 func New() error {
 	log.Println("validating DB details")
-	if conf.Get(DBType) == "" || conf.Get(DBName) == "" || conf.Get(DBHost) == "" || conf.Get(DBPort) == "" ||
+
+	// SQLite only needs the path of the DB file
+	if strings.EqualFold(conf.Get(DBType), "sqlite") {
+		if conf.Get(DBName) == "" {
+			return errMissingSQLiteParams
+		}
+	} else if conf.Get(DBType) == "" || conf.Get(DBName) == "" || conf.Get(DBHost) == "" || conf.Get(DBPort) == "" ||
 		conf.Get(DBUser) == "" || conf.Get(DBPassword) == "" {
 		return errMissingDBParams
 	}
@@ -74,6 +84,14 @@ func New() error {
 		dbHandler, err = newPostgresDB(store)
 		return err
 
+	case "sqlite":
+		dbHandler, err = newSQLiteDB(store)
+		return err
+
+	case "mysql", "mariadb":
+		dbHandler, err = newMySQLDB(store)
+		return err
+
 	default:
 		return fmt.Errorf(errStrInvalidStorageType, store.DBType)
 	}
@@ -7,6 +7,7 @@ import (
 	"treco/conf"
 
 	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -90,3 +91,115 @@ func TestGetHandler(t *testing.T) {
 	handler := Handler()
 	require.IsType(t, (*DBHandler)(nil), handler)
 }
+
+func TestMissingSQLiteDetails(t *testing.T) {
+	conf.Set(DBType, "sqlite")
+	conf.Set(DBName, "")
+	_ = os.Unsetenv(DBName)
+
+	err := New()
+	require.Error(t, err)
+	require.Equal(t, errMissingSQLiteParams, err)
+}
+
+func TestValidMySQLDBType(t *testing.T) {
+	//Set data
+	conf.Set(DBHost, "localhost")
+	conf.Set(DBUser, "some_user")
+	conf.Set(DBPassword, "some_password")
+	conf.Set(DBPort, "3306")
+	conf.Set(DBName, "some_db")
+
+	//Mock
+	connectToDB := connectToMySQLDB
+	dsns := make([]string, 0)
+	connectToMySQLDB = func(dsn string) (*gorm.DB, error) {
+		dsns = append(dsns, dsn)
+		return &gorm.DB{}, nil
+	}
+
+	for _, dbType := range []string{"mysql", "MariaDB"} {
+		conf.Set(DBType, dbType)
+
+		t.Run(dbType, func(t *testing.T) {
+			//Test
+			err := New()
+			require.NoError(t, err)
+			require.IsType(t, MySQL{}, dbHandler)
+		})
+	}
+
+	require.Equal(t, "some_user:some_password@tcp(localhost:3306)/some_db?charset=utf8mb4&parseTime=true", dsns[0])
+
+	//Reset
+	connectToMySQLDB = connectToDB
+}
+
+func TestMySQLIndexedStringColumns(t *testing.T) {
+	type entity struct {
+		ID      uint   `gorm:"primarykey"`
+		Name    string `gorm:"uniqueIndex:ui_entity"`
+		Service string `gorm:"uniqueIndex:ui_entity;size:64"`
+		Message string
+	}
+
+	dialector := mysqlDialector{Dialector: mysql.New(mysql.Config{
+		DSN:                       "user:password@tcp(localhost:3306)/db",
+		SkipInitializeWithVersion: true,
+	}).(*mysql.Dialector)}
+
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	stmt := &gorm.Statement{DB: db}
+	require.NoError(t, stmt.Parse(&entity{}))
+
+	migrator := db.Migrator().(mysql.Migrator)
+	require.Equal(t, "varchar(191) COLLATE utf8mb4_bin", migrator.Migrator.DataTypeOf(stmt.Schema.LookUpField("Name")))
+	require.Equal(t, "varchar(64) COLLATE utf8mb4_bin", migrator.Migrator.DataTypeOf(stmt.Schema.LookUpField("Service")))
+	require.Equal(t, "longtext", migrator.Migrator.DataTypeOf(stmt.Schema.LookUpField("Message")))
+
+	// Statements are only built on a dry run, hence they are captured before they would be executed
+	statements := make([]string, 0)
+	require.NoError(t, db.Callback().Raw().Before("gorm:raw").Register("capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+	require.NoError(t, migrator.CreateTable(&entity{}))
+	require.Len(t, statements, 1)
+	require.Contains(t, statements[0], "`name` varchar(191) COLLATE utf8mb4_bin,")
+	require.Contains(t, statements[0], "`service` varchar(64) COLLATE utf8mb4_bin,")
+}
+
+func TestMySQLStringPrimaryKeyColumns(t *testing.T) {
+	type label struct {
+		ID string `gorm:"primaryKey"`
+	}
+
+	type item struct {
+		ID     uint    `gorm:"primarykey"`
+		Labels []label `gorm:"many2many:item_labels"`
+	}
+
+	dialector := mysqlDialector{Dialector: mysql.New(mysql.Config{
+		DSN:                       "user:password@tcp(localhost:3306)/db",
+		SkipInitializeWithVersion: true,
+	}).(*mysql.Dialector)}
+
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	stmt := &gorm.Statement{DB: db}
+	require.NoError(t, stmt.Parse(&item{}))
+	joinTable := stmt.Schema.Relationships.Relations["Labels"].JoinTable
+
+	migrator := db.Migrator().(mysql.Migrator)
+	require.Equal(t, "varchar(191) COLLATE utf8mb4_bin", migrator.Migrator.DataTypeOf(joinTable.LookUpField("LabelID")))
+
+	statements := make([]string, 0)
+	require.NoError(t, db.Callback().Raw().Before("gorm:raw").Register("capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+	require.NoError(t, migrator.CreateTable(&label{}))
+	require.Len(t, statements, 1)
+	require.Contains(t, statements[0], "`id` varchar(191) COLLATE utf8mb4_bin,")
+}